@@ -4,7 +4,11 @@ package githubapp
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v41/github"
@@ -18,6 +22,16 @@ type AppsJWTAPI interface {
 	CreateInstallationToken(ctx context.Context, id int64, opt *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error)
 }
 
+// AppsFindAPI is an optional extension of AppsJWTAPI for clients that can look up the installation for an owner. If the
+// client passed to New implements it, WithOwners looks up the allowed owners instead of listing all installations.
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_find_api.go . AppsFindAPI
+type AppsFindAPI interface {
+	AppsJWTAPI
+	FindOrganizationInstallation(ctx context.Context, org string) (*github.Installation, *github.Response, error)
+	FindUserInstallation(ctx context.Context, user string) (*github.Installation, *github.Response, error)
+}
+
 // AppsTokenAPI is the interface that is satisfied by the Apps client when authenticated with an installation token.
 //
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_token_api.go . AppsTokenAPI
@@ -25,14 +39,37 @@ type AppsTokenAPI interface {
 	ListRepos(ctx context.Context, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error)
 }
 
+// AppsRevokeAPI is an optional extension of AppsTokenAPI for clients that can revoke the installation token they are
+// authenticated with. If the clients returned by an InstallationClientFactory do not implement it, tokens are revoked
+// with the default installation client instead.
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_revoke_api.go . AppsRevokeAPI
+type AppsRevokeAPI interface {
+	AppsTokenAPI
+	RevokeInstallationToken(ctx context.Context) (*github.Response, error)
+}
+
+// Authenticator is the interface that is satisfied by App, and can be used to replace it with a fake in tests.
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_authenticator.go . Authenticator
+type Authenticator interface {
+	CreateInstallationToken(owner string, repositories []string, permissions *Permissions) (*Token, error)
+	Installations() ([]*Installation, error)
+	Repositories(owner string) ([]*Repository, error)
+	InstallationClient(owner string, repositories []string, permissions *Permissions) (*InstallationClient, error)
+	CreateInstallationTokenContext(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*Token, error)
+	InstallationsContext(ctx context.Context) ([]*Installation, error)
+	RepositoriesContext(ctx context.Context, owner string) ([]*Repository, error)
+	InstallationClientContext(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*InstallationClient, error)
+}
+
+var _ Authenticator = &App{}
+
 // New returns a new App.
 func New(client AppsJWTAPI, options ...option) *App {
 	a := &App{
 		client:         client,
 		updateInterval: 1 * time.Minute,
-		installsClientFactory: func(token string) AppsTokenAPI {
-			return NewInstallationClient(token).V3.Apps
-		},
 	}
 	for _, option := range options {
 		option(a)
@@ -49,169 +86,617 @@ func WithUpdateInterval(duration time.Duration) option {
 	}
 }
 
-// WithInstallationClientFactory sets the function used to create new installation clients internally, and can be used to inject test fakes.
-func WithInstallationClientFactory(f func(token string) AppsTokenAPI) option {
+// WithRepositoryUpdateInterval overrides the update interval for repositories only. This can be used to reduce the
+// number of API calls for large installations when the cache is kept up to date with UpdateFromEvent.
+func WithRepositoryUpdateInterval(duration time.Duration) option {
+	return func(a *App) {
+		a.repositoryUpdateInterval = duration
+	}
+}
+
+// WithOwners restricts the App to the installations for the given owners. Only these installations are fetched and cached,
+// and requests for any other owner fail with ErrOwnerNotAllowed.
+func WithOwners(owners ...string) option {
+	return func(a *App) {
+		a.owners = make(map[string]bool, len(owners))
+		for _, owner := range owners {
+			a.owners[strings.ToLower(owner)] = true
+		}
+	}
+}
+
+// InstallationClientFactory returns an AppsTokenAPI client that is authenticated with the installation token.
+type InstallationClientFactory func(token string) AppsTokenAPI
+
+// WithInstallationClientFactory sets the function used to create the installation clients that the App uses internally to list
+// repositories and revoke tokens, e.g. to inject test fakes. It does not apply to the clients and transports returned by the App
+// (e.g. InstallationClient, InstallationTransport or Do), which can be pointed at another host with WithEndpoint.
+func WithInstallationClientFactory(f InstallationClientFactory) option {
 	return func(a *App) {
 		a.installsClientFactory = f
 	}
 }
 
+// WithPartialRepositories allows CreateInstallationToken to return a token scoped to the requested repositories that were found,
+// instead of failing if some of them are not part of the installation. Repositories that were left out are listed in Token.SkippedRepositories.
+// An error is still returned if none of the requested repositories are found. To find out which repositories exist, the repositories
+// are listed and cached for installations with access to all repositories too, which are otherwise passed on to Github by name. With
+// WithStaticInstallations the repositories are never listed, so they are still passed on by name for those installations.
+func WithPartialRepositories() option {
+	return func(a *App) {
+		a.partialRepositories = true
+	}
+}
+
 // App wraps the AppsAPI client and caches the installations and repositories for the installation.
 type App struct {
-	client                AppsJWTAPI
-	installs              []*installation
-	installsUpdatedAt     time.Time
-	installsClientFactory func(string) AppsTokenAPI
-	updateInterval        time.Duration
+	mu                       sync.Mutex
+	client                   AppsJWTAPI
+	installs                 map[string]*installation
+	installsUpdatedAt        time.Time
+	installsClientFactory    InstallationClientFactory
+	updateInterval           time.Duration
+	repositoryUpdateInterval time.Duration
+	partialRepositories      bool
+	leases                   map[string]*Lease
+	usage                    map[string]*Usage
+	usageSink                UsageSink
+	limiter                  *rateLimiter
+	tokenLifetime            time.Duration
+	freshCloneURLs           bool
+	cloneTokens              map[string]*Token
+	doOnce                   sync.Once
+	doClient                 *http.Client
+	changeHandler            func(InstallationChange)
+	changes                  []InstallationChange
+	owners                   map[string]bool
+	endpoints                map[string]*url.URL
+	faults                   *Faults
+	installsStrategy         CacheStrategy
+	repositoriesStrategy     CacheStrategy
+	fills                    map[string]*fill
+	concurrency              *concurrencyLimiter
+	latencies                latencies
+	tokenCache               *tokenCache
+	offline                  bool
+	pins                     map[string]int64
+	responseHook             ResponseHook
 }
 
 type installation struct {
 	ID                    int64
 	Owner                 string
-	Repositories          []*repository
+	RepositorySelection   string
+	Permissions           *Permissions
+	Events                []string
+	Suspended             bool
+	Repositories          map[string]repository
 	RepositoriesUpdatedAt time.Time
+	generation            uint64
 }
 
 type repository struct {
+	ID         int64
+	generation uint64
+}
+
+// Repository describes a repository that is available to an App installation.
+type Repository struct {
 	ID   int64
 	Name string
 }
 
+// AppsClient returns the client that is used to make requests authenticated as the App, i.e. the client passed to New. App
+// endpoints that are not covered by AppsJWTAPI can be called with a http.Client from NewJWTHTTPClient.
+func (a *App) AppsClient() AppsJWTAPI {
+	return a.client
+}
+
+// Installation describes an App installation.
+type Installation struct {
+	ID                  int64
+	Owner               string
+	RepositorySelection string
+	Permissions         *Permissions
+	Events              []string
+	Suspended           bool
+}
+
+// Installation returns the installation for the given owner.
+func (a *App) Installation(owner string) (*Installation, error) {
+	return a.InstallationContext(context.Background(), owner)
+}
+
+// InstallationContext is like Installation, but uses the context for requests made to refresh the cache.
+func (a *App) InstallationContext(ctx context.Context, owner string) (*Installation, error) {
+	owner = strings.ToLower(owner)
+	a.mu.Lock()
+	defer a.unlock()
+
+	if _, err := a.getInstallationID(ctx, owner); err != nil {
+		return nil, err
+	}
+	if err := a.describePinned(ctx, owner); err != nil {
+		return nil, err
+	}
+	i, ok := a.installs[owner]
+	if !ok {
+		return nil, ErrInstallationNotFound(owner)
+	}
+	return i.export(), nil
+}
+
+// Installations returns all installations of the App.
+func (a *App) Installations() ([]*Installation, error) {
+	return a.InstallationsContext(context.Background())
+}
+
+// InstallationsContext is like Installations, but uses the context for requests made to refresh the cache.
+func (a *App) InstallationsContext(ctx context.Context) ([]*Installation, error) {
+	a.mu.Lock()
+	defer a.unlock()
+
+	if err := a.updateInstallations(ctx); err != nil {
+		return nil, err
+	}
+	installations := make([]*Installation, 0, len(a.installs))
+	for _, i := range a.installs {
+		installations = append(installations, i.export())
+	}
+	sort.Slice(installations, func(i, j int) bool { return installations[i].Owner < installations[j].Owner })
+	return installations, nil
+}
+
+// Repositories returns the repositories that are available to the installation for the given owner.
+func (a *App) Repositories(owner string) ([]*Repository, error) {
+	return a.RepositoriesContext(context.Background(), owner)
+}
+
+// RepositoriesContext is like Repositories, but uses the context for requests made to refresh the cache.
+func (a *App) RepositoriesContext(ctx context.Context, owner string) ([]*Repository, error) {
+	owner = strings.ToLower(owner)
+	a.mu.Lock()
+	defer a.unlock()
+
+	if _, err := a.getInstallationID(ctx, owner); err != nil {
+		return nil, err
+	}
+	if err := a.updateRepositories(ctx, owner); err != nil {
+		return nil, err
+	}
+	i, ok := a.installs[owner]
+	if !ok {
+		return nil, ErrInstallationNotFound(owner)
+	}
+	repositories := make([]*Repository, 0, len(i.Repositories))
+	for name, r := range i.Repositories {
+		repositories = append(repositories, &Repository{ID: r.ID, Name: name})
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].Name < repositories[j].Name })
+	return repositories, nil
+}
+
+// InstallationClient returns a client that is authenticated with a new installation token for the given owner, scoped
+// to the provided repositories and permissions. The client is not refreshed when the token expires.
+func (a *App) InstallationClient(owner string, repositories []string, permissions *Permissions) (*InstallationClient, error) {
+	return a.InstallationClientContext(context.Background(), owner, repositories, permissions)
+}
+
+// InstallationClientContext is like InstallationClient, but uses the context for all requests made to create the token.
+func (a *App) InstallationClientContext(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*InstallationClient, error) {
+	token, err := a.CreateInstallationTokenContext(ctx, owner, repositories, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return newInstallationClient(a.installationHTTPClient(owner, token.GetToken())), nil
+}
+
+// export returns the exported representation of the installation.
+func (i *installation) export() *Installation {
+	return &Installation{
+		ID:                  i.ID,
+		Owner:               i.Owner,
+		RepositorySelection: i.RepositorySelection,
+		Permissions:         i.Permissions,
+		Events:              i.Events,
+		Suspended:           i.Suspended,
+	}
+}
+
 // Permissions is re-exported to prevent issues with conflicting go-github versions.
 type Permissions github.InstallationPermissions
 
 // Token is re-exported to prevent issues with conflicting go-github versions.
 type Token struct {
 	*github.InstallationToken
+
+	// SkippedRepositories lists the requested repositories that were not found and left out of the token scope (see WithPartialRepositories).
+	SkippedRepositories []string
 }
 
 // CreateInstallationToken returns a new installation token for the given owner, scoped to the provided repositories and permissions.
 func (a *App) CreateInstallationToken(owner string, repositories []string, permissions *Permissions) (*Token, error) {
-	installationID, err := a.getInstallationID(owner)
+	return a.CreateInstallationTokenContext(context.Background(), owner, repositories, permissions)
+}
+
+// CreateInstallationTokenContext is like CreateInstallationToken, but uses the context for all requests made to create the
+// token, including requests to refresh the cache.
+func (a *App) CreateInstallationTokenContext(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*Token, error) {
+	if a.tokenCache == nil {
+		return a.createInstallationToken(ctx, owner, repositories, permissions)
+	}
+	key := tokenCacheKey(owner, repositories, permissions)
+	if token, ok := a.tokenCache.get(key); ok {
+		return token, nil
+	}
+	token, err := a.createInstallationToken(ctx, owner, repositories, permissions)
 	if err != nil {
 		return nil, err
 	}
-	tokenOptions := &github.InstallationTokenOptions{
-		Permissions: (*github.InstallationPermissions)(permissions),
+	a.tokenCache.put(key, token)
+	return token, nil
+}
+
+// createInstallationToken creates a new installation token without using the token cache. It is used internally wherever
+// the caller needs a token of its own, e.g. because it is renewed or revoked.
+func (a *App) createInstallationToken(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*Token, error) {
+	owner = strings.ToLower(owner)
+	if err := permissions.Validate(); err != nil {
+		return nil, err
 	}
-	for _, repo := range repositories {
-		id, err := a.getRepositoryID(owner, repo)
-		if err != nil {
-			return nil, err
-		}
-		tokenOptions.RepositoryIDs = append(tokenOptions.RepositoryIDs, id)
+	a.mu.Lock()
+	installationID, tokenOptions, err := a.resolve(ctx, owner, repositories)
+	a.unlock()
+
+	var skipped []string
+	if e, ok := err.(*ErrRepositoryNotFound); ok && a.partialRepositories && len(tokenOptions.RepositoryIDs) > 0 {
+		skipped, err = e.Repositories, nil
 	}
-	installationToken, _, err := a.client.CreateInstallationToken(context.TODO(), installationID, tokenOptions)
 	if err != nil {
 		return nil, err
 	}
-	return &Token{InstallationToken: installationToken}, nil
+	tokenOptions.Permissions = (*github.InstallationPermissions)(permissions)
+	if err := a.limiter.wait(ctx, appsHost); err != nil {
+		return nil, err
+	}
+	release, err := a.concurrency.acquire(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	installationToken, response, err := a.client.CreateInstallationToken(ctx, installationID, tokenOptions)
+	a.latencies.since(OperationMint, start)
+	a.observe(OperationMint, response)
+	release()
+	if err != nil {
+		return nil, a.pinnedError(owner, installationID, response, err)
+	}
+	token := a.newToken(owner, installationToken)
+	token.SkippedRepositories = skipped
+	return token, nil
+}
+
+// resolve looks up the installation ID for the owner and returns token options scoped to the given repositories. If the
+// installation has access to all repositories for the owner, the repositories are passed on by name unless partial repositories
+// are allowed and the repositories can be listed. Otherwise they are validated against the repositories of the installation, and if some of them are not found, the
+// IDs of the repositories that were found are returned along with ErrRepositoryNotFound. The caller must hold a.mu, which is
+// released while the cache is refreshed.
+func (a *App) resolve(ctx context.Context, owner string, repositories []string) (int64, *github.InstallationTokenOptions, error) {
+	installationID, err := a.getInstallationID(ctx, owner)
+	if err != nil {
+		return 0, nil, err
+	}
+	tokenOptions := &github.InstallationTokenOptions{}
+	if len(repositories) == 0 {
+		return installationID, tokenOptions, nil
+	}
+	if err := a.describePinned(ctx, owner); err != nil {
+		return 0, nil, err
+	}
+	i, ok := a.installs[owner]
+	if !ok {
+		return 0, nil, ErrInstallationNotFound(owner)
+	}
+	if i.RepositorySelection == "all" && (!a.partialRepositories || a.offline) {
+		tokenOptions.Repositories = repositories
+		return installationID, tokenOptions, nil
+	}
+	tokenOptions.RepositoryIDs, err = a.getRepositoryIDs(ctx, owner, repositories)
+	return installationID, tokenOptions, err
 }
 
 // getInstallation gets the installation ID for the specified owner.
-func (a *App) getInstallationID(owner string) (int64, error) {
-	if err := a.updateInstallations(); err != nil {
+func (a *App) getInstallationID(ctx context.Context, owner string) (int64, error) {
+	if a.owners != nil && !a.owners[owner] {
+		return 0, ErrOwnerNotAllowed(owner)
+	}
+	if i, ok := a.pinnedInstallation(owner); ok {
+		return i.ID, nil
+	}
+	if err := a.updateInstallations(ctx); err != nil {
 		return 0, err
 	}
+	if i, ok := a.installs[owner]; ok {
+		return i.ID, nil
+	}
+	return 0, ErrInstallationNotFound(owner)
+}
+
+// getOwner gets the owner of the installation with the specified ID, or an empty string if it is not cached.
+func (a *App) getOwner(installationID int64) string {
 	for _, i := range a.installs {
-		if i.Owner == owner {
-			return i.ID, nil
+		if i.ID == installationID {
+			return i.Owner
 		}
 	}
-	return 0, ErrInstallationNotFound(owner)
+	return ""
 }
 
-// updateInstallations refreshes the installations on a set interval.
-func (a *App) updateInstallations() error {
-	if a.installsUpdatedAt.Add(a.updateInterval).After(time.Now()) {
+// updateInstallations refreshes the installations on a set interval, depending on the cache strategy. The caller must hold
+// a.mu, which is released while the installations are listed.
+func (a *App) updateInstallations(ctx context.Context) error {
+	if !a.refreshDue(a.installsStrategy, installationsKey, a.installsUpdatedAt, a.updateInterval, a.fetchInstallations) {
 		return nil
 	}
+	if err := a.checkDeadline(ctx, OperationListInstallations); err != nil {
+		return err
+	}
+	return a.fillCache(ctx, installationsKey, a.fetchInstallations)
+}
+
+// fetchInstallations lists the installations, and returns the update of the cache.
+func (a *App) fetchInstallations(ctx context.Context) (func(), error) {
+	list, err := a.listInstallations(ctx)
+	return func() { a.setInstallations(list) }, err
+}
+
+// setInstallations updates the cache with the listed installations. Installations that are unchanged keep their cached
+// repositories. The caller must hold a.mu.
+func (a *App) setInstallations(list []*github.Installation) {
+	installs := make(map[string]*installation, len(list))
+	for _, i := range list {
+		owner := strings.ToLower(i.Account.GetLogin())
+		ii, ok := a.installs[owner]
+		if !ok || ii.ID != i.GetID() {
+			ii = &installation{ID: i.GetID(), Owner: owner, Suspended: i.SuspendedAt != nil}
+		}
+		ii.RepositorySelection = i.GetRepositorySelection()
+		ii.Permissions = (*Permissions)(i.Permissions)
+		ii.Events = i.Events
+		installs[owner] = ii
+
+		if suspended := i.SuspendedAt != nil; ii.Suspended != suspended {
+			ii.Suspended = suspended
+			if suspended {
+				a.notify(ChangeSuspended, ii)
+			} else {
+				a.notify(ChangeUnsuspended, ii)
+			}
+		}
+	}
 
-	var installs []*installation
-	var listOptions = &github.ListOptions{PerPage: 10}
+	a.keepPinned(installs)
 
+	if !a.installsUpdatedAt.IsZero() {
+		for _, owner := range sortedOwners(installs) {
+			if old, ok := a.installs[owner]; !ok || old != installs[owner] {
+				a.notify(ChangeInstalled, installs[owner])
+			}
+		}
+		for _, owner := range sortedOwners(a.installs) {
+			if ii, ok := installs[owner]; !ok || ii != a.installs[owner] {
+				a.notify(ChangeUninstalled, a.installs[owner])
+			}
+		}
+	}
+	a.installs, a.installsUpdatedAt = installs, time.Now()
+}
+
+// sortedOwners returns the owners of the installations in order, so that changes are notified in a deterministic order.
+func sortedOwners(installs map[string]*installation) []string {
+	owners := make([]string, 0, len(installs))
+	for owner := range installs {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// listInstallations lists all installations of the App, or only the installations for the allowed owners (see WithOwners).
+// It does not use the cache, and can be called without holding a.mu.
+func (a *App) listInstallations(ctx context.Context) ([]*github.Installation, error) {
+	defer a.latencies.since(OperationListInstallations, time.Now())
+
+	if finder, ok := a.client.(AppsFindAPI); ok && a.owners != nil {
+		return a.findInstallations(ctx, finder)
+	}
+
+	var (
+		installs    []*github.Installation
+		listOptions = &github.ListOptions{PerPage: 100}
+	)
 	for {
-		list, response, err := a.client.ListInstallations(context.TODO(), listOptions)
+		if err := a.limiter.wait(ctx, appsHost); err != nil {
+			return nil, err
+		}
+		list, response, err := a.client.ListInstallations(ctx, listOptions)
+		a.observe(OperationListInstallations, response)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, i := range list {
-			installs = append(installs, &installation{
-				ID:    i.GetID(),
-				Owner: strings.ToLower(i.Account.GetLogin()),
-			})
+			if a.owners == nil || a.owners[strings.ToLower(i.GetAccount().GetLogin())] {
+				installs = append(installs, i)
+			}
 		}
 		if response.NextPage == 0 {
 			break
 		}
 		listOptions.Page = response.NextPage
 	}
-
-	a.installs, a.installsUpdatedAt = installs, time.Now()
-	return nil
+	return installs, nil
 }
 
-// getInstallation gets the repository ID for the repository.
-func (a *App) getRepositoryID(owner, repo string) (int64, error) {
-	if err := a.updateRepositories(owner); err != nil {
-		return 0, err
-	}
-	for _, i := range a.installs {
-		if i.Owner == owner {
-			for _, r := range i.Repositories {
-				if r.Name == repo {
-					return r.ID, nil
+// findInstallations looks up the installation for each of the allowed owners, which can be either an organization or a user.
+// Owners that have not installed the App are skipped.
+func (a *App) findInstallations(ctx context.Context, finder AppsFindAPI) ([]*github.Installation, error) {
+	var installs []*github.Installation
+	for owner := range a.owners {
+		for _, find := range []func(context.Context, string) (*github.Installation, *github.Response, error){
+			finder.FindOrganizationInstallation,
+			finder.FindUserInstallation,
+		} {
+			if err := a.limiter.wait(ctx, appsHost); err != nil {
+				return nil, err
+			}
+			i, response, err := find(ctx, owner)
+			a.observe(OperationListInstallations, response)
+			if err != nil {
+				if response != nil && response.StatusCode == http.StatusNotFound {
+					continue
 				}
+				return nil, err
 			}
+			installs = append(installs, i)
+			break
 		}
 	}
-
-	return 0, ErrInstallationNotFound(fmt.Sprintf("%s/%s", owner, repo))
+	return installs, nil
 }
 
-// updateRepositories refreshes the list of repositories for the specified owner on a set interval.
-func (a *App) updateRepositories(owner string) error {
-	var i *installation
-	for _, ii := range a.installs {
-		if ii.Owner == owner {
-			i = ii
+// getRepositoryIDs gets the repository IDs for the repositories, and returns an error listing all repositories that
+// were not found in the installation along with the IDs that were found.
+func (a *App) getRepositoryIDs(ctx context.Context, owner string, repositories []string) ([]int64, error) {
+	if err := a.updateRepositories(ctx, owner); err != nil {
+		return nil, err
+	}
+	i, ok := a.installs[owner]
+	if !ok {
+		return nil, ErrInstallationNotFound(owner)
+	}
+	var (
+		ids     []int64
+		missing []string
+	)
+	for _, repo := range repositories {
+		r, ok := i.Repositories[repo]
+		if !ok {
+			missing = append(missing, repo)
+			continue
 		}
+		ids = append(ids, r.ID)
 	}
+	if len(missing) > 0 {
+		return ids, &ErrRepositoryNotFound{Owner: owner, Repositories: missing}
+	}
+	return ids, nil
+}
 
-	if i.RepositoriesUpdatedAt.Add(a.updateInterval).After(time.Now()) {
+// updateRepositories refreshes the list of repositories for the specified owner on a set interval, depending on the cache strategy.
+// The caller must hold a.mu, which is released while the repositories are listed.
+func (a *App) updateRepositories(ctx context.Context, owner string) error {
+	interval := a.updateInterval
+	if a.repositoryUpdateInterval > 0 {
+		interval = a.repositoryUpdateInterval
+	}
+	i := a.installs[owner]
+	fetch := a.fetchRepositories(owner, i.ID)
+	if !a.refreshDue(a.repositoriesStrategy, repositoriesKey(owner), i.RepositoriesUpdatedAt, interval, fetch) {
 		return nil
 	}
+	if err := a.checkDeadline(ctx, OperationListRepositories); err != nil {
+		return err
+	}
+	return a.fillCache(ctx, repositoriesKey(owner), fetch)
+}
+
+// fetchRepositories returns a function that lists the repositories of the installation, and returns the update of the cache.
+func (a *App) fetchRepositories(owner string, id int64) func(context.Context) (func(), error) {
+	return func(ctx context.Context) (func(), error) {
+		list, err := a.listRepositories(ctx, owner, id)
+		return func() { a.setRepositories(owner, id, list) }, err
+	}
+}
 
-	token, err := a.CreateInstallationToken(owner, nil, &Permissions{})
+// listRepositories lists the repositories of the installation. It does not use the cache, and must be called without holding
+// a.mu since it waits for a concurrency slot of the owner (see WithInstallationConcurrency).
+func (a *App) listRepositories(ctx context.Context, owner string, id int64) ([]*github.Repository, error) {
+	defer a.latencies.since(OperationListRepositories, time.Now())
+
+	if err := a.limiter.wait(ctx, appsHost); err != nil {
+		return nil, err
+	}
+	release, err := a.concurrency.acquire(ctx, owner)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	token, response, err := a.client.CreateInstallationToken(ctx, id, &github.InstallationTokenOptions{
+		Permissions: &github.InstallationPermissions{},
+	})
+	a.observe(OperationListRepositories, response)
+	release()
+	if err != nil {
+		return nil, a.pinnedError(owner, id, response, err)
 	}
 
 	var (
-		repositories []*repository
+		repositories []*github.Repository
 		listOptions  = &github.ListOptions{PerPage: 100}
-		client       = a.installsClientFactory(*token.Token)
+		client       = a.tokenClient(owner, token.GetToken())
 	)
-
 	for {
-		list, response, err := client.ListRepos(context.TODO(), listOptions)
+		list, response, err := client.ListRepos(ctx, listOptions)
+		a.observe(OperationListRepositories, response)
 		if err != nil {
-			return err
-		}
-		for _, r := range list.Repositories {
-			repositories = append(repositories, &repository{
-				ID:   r.GetID(),
-				Name: r.GetName(),
-			})
+			return nil, err
 		}
+		repositories = append(repositories, list.Repositories...)
 		if response.NextPage == 0 {
 			break
 		}
 		listOptions.Page = response.NextPage
 	}
+	return repositories, nil
+}
+
+// setRepositories updates the cached repositories of the installation with the listed repositories, unless the installation
+// has been removed or replaced since they were listed. The cached repositories are updated in place and marked with the
+// current generation, so that repositories which were not listed can be removed without allocating a new cache. The caller
+// must hold a.mu.
+func (a *App) setRepositories(owner string, id int64, list []*github.Repository) {
+	i, ok := a.installs[owner]
+	if !ok || i.ID != id {
+		return
+	}
+	initial := i.Repositories == nil
+	if initial {
+		i.Repositories = make(map[string]repository, len(list))
+	}
+	i.generation++
+
+	var added, removed []string
+	for _, r := range list {
+		if _, ok := i.Repositories[r.GetName()]; !ok && !initial {
+			added = append(added, r.GetName())
+		}
+		i.Repositories[r.GetName()] = repository{ID: r.GetID(), generation: i.generation}
+	}
 
-	i.Repositories, i.RepositoriesUpdatedAt = repositories, time.Now()
-	return nil
+	for name, r := range i.Repositories {
+		if r.generation != i.generation {
+			delete(i.Repositories, name)
+			removed = append(removed, name)
+		}
+	}
+	if len(added) > 0 {
+		sort.Strings(added)
+		a.notify(ChangeRepositoriesAdded, i, added...)
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		a.notify(ChangeRepositoriesRemoved, i, removed...)
+	}
+	i.RepositoriesUpdatedAt = time.Now()
 }
 
 // ErrInstallationNotFound is returned if the requested App installation is not found.
@@ -221,6 +706,25 @@ func (e ErrInstallationNotFound) Error() string {
 	return fmt.Sprintf("installation not found: '%s'", string(e))
 }
 
+// ErrOwnerNotAllowed is returned if the requested owner is not allowed by WithOwners.
+type ErrOwnerNotAllowed string
+
+func (e ErrOwnerNotAllowed) Error() string {
+	return fmt.Sprintf("owner not allowed: '%s'", string(e))
+}
+
+// ErrRepositoryNotFound is returned if one or more of the requested repositories are not found in the App installation. The
+// repositories of installations with access to all repositories are only checked with WithPartialRepositories, otherwise
+// Github rejects the token request if they do not exist.
+type ErrRepositoryNotFound struct {
+	Owner        string
+	Repositories []string
+}
+
+func (e *ErrRepositoryNotFound) Error() string {
+	return fmt.Sprintf("repositories not found in installation '%s': '%s'", e.Owner, strings.Join(e.Repositories, "', '"))
+}
+
 func stringPointer(s string) *string {
 	if s == "" {
 		return nil
@@ -4,46 +4,28 @@
 package githubapp_test
 
 import (
-	"io/ioutil"
-	"os"
-	"strconv"
+	"context"
 	"testing"
 
 	"github.com/google/go-github/v41/github"
 	"github.com/telia-oss/githubapp"
-)
-
-var (
-	appIntegrationID  = os.Getenv("GITHUB_APP_INTEGRATION_ID")
-	appPrivateKeyFile = os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE")
-	targetOwner       = os.Getenv("GITHUB_APP_TARGET_ORG")
-	targetRepository  = os.Getenv("GITHUB_APP_TARGET_REPOSITORY")
+	"github.com/telia-oss/githubapp/e2etest"
 )
 
 func TestGithubAppE2E(t *testing.T) {
-	integrationID, err := strconv.ParseInt(appIntegrationID, 10, 64)
-	noError(t, err)
-
-	privateKey, err := ioutil.ReadFile(appPrivateKeyFile)
-	noError(t, err)
-
-	client, err := githubapp.NewClient(integrationID, privateKey)
-	noError(t, err)
-
-	app := githubapp.New(client)
-
-	token, err := app.CreateInstallationToken(
-		targetOwner,
-		[]string{targetRepository},
-		&githubapp.Permissions{
+	var (
+		config      = e2etest.ConfigFromEnv(t)
+		app         = config.App(t)
+		permissions = &githubapp.Permissions{
 			Metadata: github.String("read"),
-		},
+		}
 	)
-	noError(t, err)
 
-	isEqual(t, "", token.Permissions.GetChecks())
-	isEqual(t, "read", token.Permissions.GetMetadata())
-	for _, r := range token.Repositories {
-		isEqual(t, targetRepository, r.GetName())
+	if err := githubapp.ValidateCredentials(context.TODO(), config.IntegrationID, config.PrivateKey); err != nil {
+		t.Fatalf("failed to validate credentials: %s", err)
 	}
+
+	token := config.Mint(t, app, []string{config.Repository}, permissions)
+	e2etest.VerifyScope(t, token, []string{config.Repository}, permissions)
+	e2etest.Revoke(t, token)
 }
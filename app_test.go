@@ -1,6 +1,10 @@
 package githubapp_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -72,3 +76,874 @@ func TestGithubApp(t *testing.T) {
 	isEqual(t, 3, client.CreateInstallationTokenCallCount())
 	isEqual(t, 1, tokenClient.ListReposCallCount())
 }
+
+func TestCreateInstallationTokenForEvent(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+	)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{
+		Token: github.String("token"),
+	}, nil, nil)
+
+	token, err := gh.CreateInstallationTokenForEvent(&github.PushEvent{
+		Installation: &github.Installation{ID: github.Int64(23)},
+	}, nil)
+	noError(t, err)
+	isEqual(t, "token", token.GetToken())
+
+	_, id, _ := client.CreateInstallationTokenArgsForCall(0)
+	isEqual(t, int64(23), id)
+	isEqual(t, 0, client.ListInstallationsCallCount())
+
+	_, err = gh.CreateInstallationTokenForEvent(&github.PushEvent{}, nil)
+	isEqual(t, "event does not contain an installation: *github.PushEvent", err.Error())
+
+	_, err = gh.CreateInstallationTokenForEvent(&github.PushEvent{
+		Installation: &github.Installation{ID: github.Int64(23)},
+	}, &githubapp.Permissions{Contents: github.String("wirte")})
+	isEqual(t, githubapp.ErrInvalidPermission{Permission: githubapp.PermissionContents, Level: "wirte"}, err)
+	isEqual(t, 1, client.CreateInstallationTokenCallCount())
+}
+
+func TestCreateInstallationTokenForEventWithOwners(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithOwners("allowed"))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("Allowed")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("other")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{
+		Token: github.String("token"),
+	}, nil, nil)
+
+	token, err := gh.CreateInstallationTokenForEvent(&github.PushEvent{
+		Installation: &github.Installation{ID: github.Int64(1)},
+	}, nil)
+	noError(t, err)
+	isEqual(t, "token", token.GetToken())
+
+	_, err = gh.CreateInstallationTokenForEvent(&github.PushEvent{
+		Installation: &github.Installation{ID: github.Int64(2), Account: &github.User{Login: github.String("other")}},
+	}, nil)
+	isEqual(t, githubapp.ErrOwnerNotAllowed("other"), err)
+	isEqual(t, 1, client.CreateInstallationTokenCallCount())
+}
+
+func TestUsage(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		sunk   []string
+		gh     = githubapp.New(client, githubapp.WithUsageSink(func(owner string, _ time.Time) {
+			sunk = append(sunk, owner)
+		}))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	for _, owner := range []string{"b", "a", "b"} {
+		_, err := gh.CreateInstallationToken(owner, nil, nil)
+		noError(t, err)
+	}
+
+	usage := gh.Usage()
+	isEqual(t, 2, len(usage))
+	isEqual(t, "a", usage[0].Owner)
+	isEqual(t, 1, usage[0].Tokens)
+	isEqual(t, "b", usage[1].Owner)
+	isEqual(t, 2, usage[1].Tokens)
+	isEqual(t, []string{"b", "a", "b"}, sunk)
+}
+
+func TestRepositoryNotFound(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("a")}},
+	}, &github.Response{}, nil)
+
+	_, err := gh.CreateInstallationToken("owner", []string{"a", "b", "c"}, nil)
+	isEqual(t, &githubapp.ErrRepositoryNotFound{Owner: "owner", Repositories: []string{"b", "c"}}, err)
+	isEqual(t, "repositories not found in installation 'owner': 'b', 'c'", err.Error())
+	isEqual(t, 1, client.CreateInstallationTokenCallCount())
+}
+
+func TestOwnerIsCaseInsensitive(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("Owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("repository")}},
+	}, &github.Response{}, nil)
+
+	_, err := gh.CreateInstallationToken("Owner", []string{"repository"}, nil)
+	noError(t, err)
+	_, id, _ := client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, int64(23), id)
+
+	installation, err := gh.Installation("OWNER")
+	noError(t, err)
+	isEqual(t, "owner", installation.Owner)
+
+	repositories, err := gh.Repositories("OWNER")
+	noError(t, err)
+	isEqual(t, 1, len(repositories))
+	isEqual(t, 1, tokenClient.ListReposCallCount())
+}
+
+func TestPartialRepositories(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory), githubapp.WithPartialRepositories())
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("a")}},
+	}, &github.Response{}, nil)
+
+	token, err := gh.CreateInstallationToken("owner", []string{"a", "b"}, nil)
+	noError(t, err)
+	isEqual(t, []string{"b"}, token.SkippedRepositories)
+
+	_, _, opts := client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, []int64{1}, opts.RepositoryIDs)
+
+	_, err = gh.CreateInstallationToken("owner", []string{"b"}, nil)
+	isEqual(t, &githubapp.ErrRepositoryNotFound{Owner: "owner", Repositories: []string{"b"}}, err)
+}
+
+func BenchmarkUpdateRepositories(b *testing.B) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory), githubapp.WithUpdateInterval(0))
+		pages         = 100
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposCalls(func(_ context.Context, opts *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+		page := opts.Page
+		if page == 0 {
+			page = 1
+		}
+		list := &github.ListRepositories{}
+		for i := 0; i < opts.PerPage; i++ {
+			id := int64((page-1)*opts.PerPage + i)
+			list.Repositories = append(list.Repositories, &github.Repository{
+				ID:   github.Int64(id),
+				Name: github.String(fmt.Sprintf("repository-%d", id)),
+			})
+		}
+		response := &github.Response{}
+		if page < pages {
+			response.NextPage = page + 1
+		}
+		return list, response, nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gh.CreateInstallationToken("owner", []string{"repository-1"}, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUpdateFromEvent(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("a")}},
+	}, &github.Response{}, nil)
+
+	_, err := gh.Repositories("owner")
+	noError(t, err)
+
+	gh.UpdateFromEvent(&github.InstallationRepositoriesEvent{
+		Installation:        &github.Installation{ID: github.Int64(23)},
+		RepositoriesAdded:   []*github.Repository{{ID: github.Int64(2), Name: github.String("b")}},
+		RepositoriesRemoved: []*github.Repository{{ID: github.Int64(1), Name: github.String("a")}},
+	})
+
+	repositories, err := gh.Repositories("owner")
+	noError(t, err)
+	isEqual(t, []*githubapp.Repository{{ID: 2, Name: "b"}}, repositories)
+	isEqual(t, 1, tokenClient.ListReposCallCount())
+}
+
+func TestWithOwners(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsFindAPI{}
+		gh     = githubapp.New(client, githubapp.WithOwners("Org"))
+	)
+
+	client.FindOrganizationInstallationReturns(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, errors.New("not found"))
+	client.FindUserInstallationReturns(&github.Installation{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("org")},
+	}, &github.Response{}, nil)
+
+	installation, err := gh.Installation("org")
+	noError(t, err)
+	isEqual(t, int64(23), installation.ID)
+
+	_, err = gh.Installation("other")
+	isEqual(t, githubapp.ErrOwnerNotAllowed("other"), err)
+
+	isEqual(t, 0, client.ListInstallationsCallCount())
+	isEqual(t, 1, client.FindOrganizationInstallationCallCount())
+	isEqual(t, 1, client.FindUserInstallationCallCount())
+}
+
+func TestStats(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}, RepositorySelection: github.String("all")},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}, RepositorySelection: github.String("selected")},
+		{ID: github.Int64(3), Account: &github.User{Login: github.String("c")}, RepositorySelection: github.String("selected"), SuspendedAt: &github.Timestamp{}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{
+			{ID: github.Int64(1), Name: github.String("x")},
+			{ID: github.Int64(2), Name: github.String("y")},
+		},
+	}, &github.Response{}, nil)
+
+	_, err := gh.Repositories("b")
+	noError(t, err)
+
+	stats, err := gh.Stats(context.TODO())
+	noError(t, err)
+	isEqual(t, 3, stats.Installations)
+	isEqual(t, 1, stats.AllRepositories)
+	isEqual(t, 2, stats.SelectedRepositories)
+	isEqual(t, 1, stats.Suspended)
+	isEqual(t, 2, stats.Repositories)
+	isEqual(t, 1, len(stats.RepositoriesUpdatedAt))
+}
+
+func TestRepositorySelectionAll(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:                  github.Int64(23),
+		Account:             &github.User{Login: github.String("owner")},
+		RepositorySelection: github.String("all"),
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	_, err := gh.CreateInstallationToken("owner", []string{"repository"}, nil)
+	noError(t, err)
+
+	_, _, opts := client.CreateInstallationTokenArgsForCall(0)
+	isEqual(t, []string{"repository"}, opts.Repositories)
+	isEqual(t, 0, len(opts.RepositoryIDs))
+	isEqual(t, 1, client.CreateInstallationTokenCallCount())
+	isEqual(t, 0, tokenClient.ListReposCallCount())
+}
+
+func TestRepositorySelectionAllWithPartialRepositories(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory), githubapp.WithPartialRepositories())
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:                  github.Int64(23),
+		Account:             &github.User{Login: github.String("owner")},
+		RepositorySelection: github.String("all"),
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("a")}},
+	}, &github.Response{}, nil)
+
+	token, err := gh.CreateInstallationToken("owner", []string{"a", "b"}, nil)
+	noError(t, err)
+	isEqual(t, []string{"b"}, token.SkippedRepositories)
+
+	_, _, opts := client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, []int64{1}, opts.RepositoryIDs)
+	isEqual(t, 0, len(opts.Repositories))
+
+	_, err = gh.CreateInstallationToken("owner", []string{"b"}, nil)
+	isEqual(t, &githubapp.ErrRepositoryNotFound{Owner: "owner", Repositories: []string{"b"}}, err)
+}
+
+func TestCacheStrategy(t *testing.T) {
+	tests := []struct {
+		description string
+		strategy    githubapp.CacheStrategy
+		expected    []int
+	}{
+		{
+			description: "read-through refreshes stale caches on use",
+			strategy:    githubapp.CacheReadThrough,
+			expected:    []int{1, 1, 2, 3},
+		},
+		{
+			description: "refresh-ahead refreshes caches in the background",
+			strategy:    githubapp.CacheRefreshAhead,
+			expected:    []int{1, 2, 2, 3},
+		},
+		{
+			description: "manual only refreshes on request",
+			strategy:    githubapp.CacheManual,
+			expected:    []int{1, 1, 1, 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				client = &fakes.FakeAppsJWTAPI{}
+				gh     = githubapp.New(client,
+					githubapp.WithUpdateInterval(200*time.Millisecond),
+					githubapp.WithCacheStrategy(tc.strategy, tc.strategy),
+				)
+			)
+
+			client.ListInstallationsReturns([]*github.Installation{
+				{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+			}, &github.Response{}, nil)
+
+			var calls []int
+			for _, wait := range []time.Duration{0, 170 * time.Millisecond, 100 * time.Millisecond} {
+				time.Sleep(wait)
+				_, err := gh.Installations()
+				noError(t, err)
+				// Give background refreshes time to complete.
+				time.Sleep(10 * time.Millisecond)
+				calls = append(calls, client.ListInstallationsCallCount())
+			}
+			noError(t, gh.Refresh(context.TODO()))
+			calls = append(calls, client.ListInstallationsCallCount())
+
+			isEqual(t, tc.expected, calls)
+		})
+	}
+}
+
+func TestCacheRefreshAheadDoesNotBlock(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client,
+			githubapp.WithUpdateInterval(100*time.Millisecond),
+			githubapp.WithCacheStrategy(githubapp.CacheRefreshAhead, githubapp.CacheRefreshAhead),
+		)
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	client.ListInstallationsCalls(func(context.Context, *github.ListOptions) ([]*github.Installation, *github.Response, error) {
+		if client.ListInstallationsCallCount() > 1 {
+			close(started)
+			<-release
+		}
+		return []*github.Installation{
+			{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+		}, &github.Response{}, nil
+	})
+
+	_, err := gh.Installations()
+	noError(t, err)
+
+	// Start a background refresh, which is blocked until it is released.
+	time.Sleep(80 * time.Millisecond)
+	_, err = gh.Installations()
+	noError(t, err)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := gh.Installation("owner")
+		noError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(50 * time.Millisecond):
+		t.Error("expected the cache to be used while it is refreshed in the background")
+	}
+	close(release)
+	<-done
+	isEqual(t, 2, client.ListInstallationsCallCount())
+}
+
+func TestListRepositoriesDoesNotBlockOtherOwners(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		started       = make(chan struct{})
+		release       = make(chan struct{})
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}},
+	}, &github.Response{}, nil)
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, &github.Response{}, nil)
+
+	tokenClient.ListReposCalls(func(context.Context, *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+		close(started)
+		<-release
+		return &github.ListRepositories{
+			Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("repository")}},
+		}, &github.Response{}, nil
+	})
+
+	// List the repositories of the first owner, which is blocked until it is released.
+	listed := make(chan struct{})
+	go func() {
+		defer close(listed)
+		_, err := gh.CreateInstallationToken("a", []string{"repository"}, nil)
+		noError(t, err)
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := gh.CreateInstallationToken("b", nil, nil)
+		noError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(50 * time.Millisecond):
+		t.Error("expected listing the repositories of one owner not to block another owner")
+	}
+	close(release)
+	<-done
+	<-listed
+	isEqual(t, 1, tokenClient.ListReposCallCount())
+}
+
+func TestRefreshDoesNotBlock(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		started       = make(chan struct{})
+		release       = make(chan struct{})
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}},
+	}, &github.Response{}, nil)
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, &github.Response{}, nil)
+
+	tokenClient.ListReposCalls(func(context.Context, *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+		if tokenClient.ListReposCallCount() > 1 {
+			close(started)
+			<-release
+		}
+		return &github.ListRepositories{
+			Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("repository")}},
+		}, &github.Response{}, nil
+	})
+
+	_, err := gh.CreateInstallationToken("a", []string{"repository"}, nil)
+	noError(t, err)
+
+	// Refresh the repositories of the first owner, which is blocked until it is released.
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		noError(t, gh.Refresh(context.TODO()))
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := gh.CreateInstallationToken("b", nil, nil)
+		noError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(50 * time.Millisecond):
+		t.Error("expected the cache to be used while it is refreshed")
+	}
+	close(release)
+	<-done
+	<-refreshed
+	isEqual(t, 2, tokenClient.ListReposCallCount())
+}
+
+func TestWouldExceedDeadline(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithUpdateInterval(0))
+	)
+
+	client.ListInstallationsCalls(func(context.Context, *github.ListOptions) ([]*github.Installation, *github.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+		return []*github.Installation{
+			{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+		}, &github.Response{}, nil
+	})
+
+	_, err := gh.Stats(context.TODO())
+	noError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = gh.Stats(ctx)
+	e, ok := err.(*githubapp.ErrWouldExceedDeadline)
+	if !ok {
+		t.Fatalf("expected ErrWouldExceedDeadline, got: %v", err)
+	}
+	isEqual(t, "list_installations", e.Operation)
+	isEqual(t, 1, client.ListInstallationsCallCount())
+}
+
+func TestLatency(t *testing.T) {
+	var (
+		client  = &fakes.FakeAppsJWTAPI{}
+		sampled []string
+		gh      = githubapp.New(client, githubapp.WithLatencySink(func(operation string, _ time.Duration) {
+			sampled = append(sampled, operation)
+		}))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		time.Sleep(time.Duration(client.CreateInstallationTokenCallCount()) * time.Millisecond)
+		return &github.InstallationToken{Token: github.String("token")}, nil, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		_, err := gh.CreateInstallationToken("owner", nil, nil)
+		noError(t, err)
+	}
+
+	latency := gh.Latency()
+	isEqual(t, 2, len(latency))
+	isEqual(t, githubapp.OperationListInstallations, latency[0].Operation)
+	isEqual(t, githubapp.OperationMint, latency[1].Operation)
+	isEqual(t, 10, latency[1].Samples)
+	if l := latency[1]; l.P50 < 5*time.Millisecond || l.P90 < l.P50 || l.P99 < l.P90 {
+		t.Errorf("unexpected percentiles: %+v", l)
+	}
+	isEqual(t, 11, len(sampled))
+}
+
+func TestCreateInstallationTokenContext(t *testing.T) {
+	type key struct{}
+
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		ctx           = context.WithValue(context.Background(), key{}, "value")
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("repository")}},
+	}, &github.Response{}, nil)
+
+	_, err := gh.CreateInstallationTokenContext(ctx, "owner", []string{"repository"}, nil)
+	noError(t, err)
+
+	listCtx, _ := client.ListInstallationsArgsForCall(0)
+	isEqual(t, "value", listCtx.Value(key{}))
+	reposCtx, _ := tokenClient.ListReposArgsForCall(0)
+	isEqual(t, "value", reposCtx.Value(key{}))
+	for i := 0; i < client.CreateInstallationTokenCallCount(); i++ {
+		tokenCtx, _, _ := client.CreateInstallationTokenArgsForCall(i)
+		isEqual(t, "value", tokenCtx.Value(key{}))
+	}
+}
+
+func TestTokenCache(t *testing.T) {
+	var (
+		client    = &fakes.FakeAppsJWTAPI{}
+		gh        = githubapp.New(client, githubapp.WithTokenCache(5*time.Minute))
+		expiresAt = time.Now().Add(1 * time.Hour)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}, RepositorySelection: github.String("all")},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		token := fmt.Sprintf("token-%d", client.CreateInstallationTokenCallCount())
+		return &github.InstallationToken{Token: github.String(token), ExpiresAt: &expiresAt}, nil, nil
+	})
+
+	read := &githubapp.Permissions{Contents: github.String("read")}
+	for _, tc := range []struct {
+		repositories []string
+		permissions  *githubapp.Permissions
+		expected     string
+	}{
+		{repositories: []string{"a", "b"}, permissions: read, expected: "token-1"},
+		{repositories: []string{"b", "a"}, permissions: &githubapp.Permissions{Contents: github.String("read")}, expected: "token-1"},
+		{repositories: []string{"a", "b"}, expected: "token-2"},
+		{repositories: []string{"a"}, permissions: read, expected: "token-3"},
+	} {
+		token, err := gh.CreateInstallationToken("owner", tc.repositories, tc.permissions)
+		noError(t, err)
+		isEqual(t, tc.expected, token.GetToken())
+	}
+
+	// Leases always get a token of their own.
+	lease, err := gh.Lease(githubapp.Scope{Owner: "owner", Repositories: []string{"a", "b"}, Permissions: read})
+	noError(t, err)
+	isEqual(t, "token-4", lease.Token().GetToken())
+}
+
+func TestStaticInstallations(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithStaticInstallations(
+			githubapp.StaticInstallation{ID: 1, Owner: "selected", Repositories: map[string]int64{"x": 10, "y": 11}},
+			githubapp.StaticInstallation{ID: 2, Owner: "all"},
+		))
+	)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	_, err := gh.CreateInstallationToken("selected", []string{"x"}, nil)
+	noError(t, err)
+	_, id, opts := client.CreateInstallationTokenArgsForCall(0)
+	isEqual(t, int64(1), id)
+	isEqual(t, []int64{10}, opts.RepositoryIDs)
+
+	_, err = gh.CreateInstallationToken("all", []string{"z"}, nil)
+	noError(t, err)
+	_, id, opts = client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, int64(2), id)
+	isEqual(t, []string{"z"}, opts.Repositories)
+
+	_, err = gh.CreateInstallationToken("selected", []string{"z"}, nil)
+	isEqual(t, &githubapp.ErrRepositoryNotFound{Owner: "selected", Repositories: []string{"z"}}, err)
+
+	_, err = gh.CreateInstallationToken("other", nil, nil)
+	isEqual(t, githubapp.ErrInstallationNotFound("other"), err)
+
+	noError(t, gh.Refresh(context.TODO()))
+	isEqual(t, 0, client.ListInstallationsCallCount())
+	isEqual(t, 2, client.CreateInstallationTokenCallCount())
+}
+
+func TestStaticInstallationsWithPartialRepositories(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithPartialRepositories(), githubapp.WithStaticInstallations(
+			githubapp.StaticInstallation{ID: 1, Owner: "selected", Repositories: map[string]int64{"x": 10}},
+			githubapp.StaticInstallation{ID: 2, Owner: "all"},
+		))
+	)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	token, err := gh.CreateInstallationToken("selected", []string{"x", "z"}, nil)
+	noError(t, err)
+	isEqual(t, []string{"z"}, token.SkippedRepositories)
+	_, _, opts := client.CreateInstallationTokenArgsForCall(0)
+	isEqual(t, []int64{10}, opts.RepositoryIDs)
+
+	token, err = gh.CreateInstallationToken("all", []string{"z"}, nil)
+	noError(t, err)
+	isEqual(t, 0, len(token.SkippedRepositories))
+	_, _, opts = client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, []string{"z"}, opts.Repositories)
+}
+
+func TestPinnedInstallations(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithPinnedInstallations(map[string]int64{"Pinned": 5}))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("other")}},
+		{ID: github.Int64(9), Account: &github.User{Login: github.String("pinned")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(_ context.Context, id int64, _ *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		if id == 5 && client.CreateInstallationTokenCallCount() > 1 {
+			response := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+			return nil, response, &github.ErrorResponse{Response: response.Response}
+		}
+		return &github.InstallationToken{Token: github.String("token")}, nil, nil
+	})
+
+	_, err := gh.CreateInstallationToken("pinned", nil, nil)
+	noError(t, err)
+	_, id, _ := client.CreateInstallationTokenArgsForCall(0)
+	isEqual(t, int64(5), id)
+	isEqual(t, 0, client.ListInstallationsCallCount())
+
+	installations, err := gh.Installations()
+	noError(t, err)
+	isEqual(t, 2, len(installations))
+	isEqual(t, int64(5), installations[1].ID)
+
+	_, err = gh.CreateInstallationToken("pinned", nil, nil)
+	isEqual(t, &githubapp.ErrPinnedInstallationNotFound{Owner: "pinned", ID: 5}, err)
+}
+
+func TestPinnedInstallationMetadata(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithPinnedInstallations(map[string]int64{"owner": 23}))
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:                  github.Int64(23),
+		Account:             &github.User{Login: github.String("owner")},
+		RepositorySelection: github.String("all"),
+		Permissions:         &github.InstallationPermissions{Contents: github.String("read")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	_, err := gh.CreateInstallationToken("owner", nil, nil)
+	noError(t, err)
+	isEqual(t, 0, client.ListInstallationsCallCount())
+
+	_, err = gh.CreateInstallationToken("owner", []string{"repository"}, nil)
+	noError(t, err)
+	_, id, opts := client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, int64(23), id)
+	isEqual(t, []string{"repository"}, opts.Repositories)
+
+	installation, err := gh.Installation("owner")
+	noError(t, err)
+	isEqual(t, "all", installation.RepositorySelection)
+	isEqual(t, &githubapp.Permissions{Contents: github.String("read")}, installation.Permissions)
+	isEqual(t, 1, client.ListInstallationsCallCount())
+}
+
+func TestResponseHook(t *testing.T) {
+	var (
+		client    = &fakes.FakeAppsJWTAPI{}
+		responses []githubapp.ResponseMetadata
+		gh        = githubapp.New(client, githubapp.WithResponseHook(func(r githubapp.ResponseMetadata) {
+			responses = append(responses, r)
+		}))
+		reset = time.Now().Add(1 * time.Hour).Truncate(time.Second)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("owner")}},
+	}, &github.Response{}, nil)
+
+	header := http.Header{}
+	header.Set("X-GitHub-Request-Id", "ABCD:1234")
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, &github.Response{
+		Response: &http.Response{StatusCode: http.StatusCreated, Header: header},
+		Rate:     github.Rate{Limit: 5000, Remaining: 4999, Reset: github.Timestamp{Time: reset}},
+	}, nil)
+
+	_, err := gh.CreateInstallationToken("owner", nil, nil)
+	noError(t, err)
+
+	// Responses without HTTP metadata (like the one for listing installations above) are not reported.
+	isEqual(t, []githubapp.ResponseMetadata{{
+		Operation:     githubapp.OperationMint,
+		StatusCode:    http.StatusCreated,
+		RequestID:     "ABCD:1234",
+		RateLimit:     5000,
+		RateRemaining: 4999,
+		RateReset:     reset,
+	}}, responses)
+}
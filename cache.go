@@ -0,0 +1,153 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CacheStrategy determines when the cached installations or repositories of the App are refreshed.
+type CacheStrategy int
+
+const (
+	// CacheReadThrough refreshes the cache when it is used after the update interval has passed, and the caller waits for
+	// the refresh. This is the default.
+	CacheReadThrough CacheStrategy = iota
+
+	// CacheRefreshAhead refreshes the cache in the background when it is used after three quarters of the update interval
+	// have passed, so that callers rarely wait for a refresh. The cache is refreshed as with CacheReadThrough if it has
+	// not been used before the update interval has passed.
+	CacheRefreshAhead
+
+	// CacheManual only fills the cache on first use. After that it is only updated by Refresh and UpdateFromEvent.
+	CacheManual
+)
+
+// WithCacheStrategy sets the strategy used to refresh the cached installations and the cached repositories of each installation.
+func WithCacheStrategy(installations, repositories CacheStrategy) option {
+	return func(a *App) {
+		a.installsStrategy = installations
+		a.repositoriesStrategy = repositories
+	}
+}
+
+// Refresh updates the cached installations, and the repositories of the installations that have been cached, regardless of
+// the cache strategy. It does nothing if WithStaticInstallations is used.
+func (a *App) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.unlock()
+
+	if a.offline {
+		return nil
+	}
+
+	if err := a.fillCache(ctx, installationsKey, a.fetchInstallations); err != nil {
+		return err
+	}
+	for _, owner := range sortedOwners(a.installs) {
+		// The installations may have changed while the repositories of the previous owner were listed.
+		i, ok := a.installs[owner]
+		if !ok || i.Repositories == nil {
+			continue
+		}
+		if err := a.fillCache(ctx, repositoriesKey(owner), a.fetchRepositories(owner, i.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshAheadTimeout bounds a background refresh, since there is no caller whose context can be used.
+const refreshAheadTimeout = 1 * time.Minute
+
+// installationsKey identifies the cached installations in a.fills.
+const installationsKey = "installations"
+
+// repositoriesKey identifies the cached repositories of the owner in a.fills.
+func repositoriesKey(owner string) string {
+	return "repositories/" + owner
+}
+
+// fill is a refresh of a cache that is in progress. Done is closed when the refresh has finished, and err is its result.
+type fill struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshDue returns true if a cache that was last updated at the given time must be refreshed before it is used. If the
+// strategy is CacheRefreshAhead and the cache is about to become stale, fetch is started in the background instead. The key
+// identifies the cache, so that it is only refreshed once at a time (see fillCache). The caller must hold a.mu.
+func (a *App) refreshDue(strategy CacheStrategy, key string, updatedAt time.Time, interval time.Duration, fetch func(context.Context) (func(), error)) bool {
+	now := time.Now()
+	switch {
+	case a.offline:
+		return false
+	case updatedAt.IsZero():
+		return true
+	case strategy == CacheManual:
+		return false
+	case !updatedAt.Add(interval).After(now):
+		return true
+	case strategy == CacheRefreshAhead && !updatedAt.Add(interval*3/4).After(now) && a.fills[key] == nil:
+		f := a.startFill(key)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), refreshAheadTimeout)
+			defer cancel()
+			// Errors are ignored since the cache is refreshed by the next caller once it becomes stale.
+			a.runFill(ctx, key, f, fetch)
+		}()
+	}
+	return false
+}
+
+// fillCache refreshes the cache identified by the key, or waits for a refresh of the same cache that is already in progress.
+// Fetch is called without holding a.mu, and the update it returns is applied while holding a.mu, so that a slow request for one
+// cache does not block callers that use another. The caller must hold a.mu, and must read any cached state again afterwards.
+func (a *App) fillCache(ctx context.Context, key string, fetch func(context.Context) (func(), error)) error {
+	for {
+		f, ok := a.fills[key]
+		if !ok {
+			f = a.startFill(key)
+			a.unlock()
+			a.runFill(ctx, key, f, fetch)
+			a.mu.Lock()
+			return f.err
+		}
+		a.unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			a.mu.Lock()
+			return ctx.Err()
+		}
+		a.mu.Lock()
+		// A refresh that failed because the context of another caller was done is retried with our own context.
+		if !errors.Is(f.err, context.Canceled) && !errors.Is(f.err, context.DeadlineExceeded) {
+			return f.err
+		}
+	}
+}
+
+// startFill marks the cache identified by the key as being refreshed. The caller must hold a.mu.
+func (a *App) startFill(key string) *fill {
+	if a.fills == nil {
+		a.fills = make(map[string]*fill)
+	}
+	f := &fill{done: make(chan struct{})}
+	a.fills[key] = f
+	return f
+}
+
+// runFill fetches the update for a refresh started with startFill, and applies it. The caller must not hold a.mu.
+func (a *App) runFill(ctx context.Context, key string, f *fill, fetch func(context.Context) (func(), error)) {
+	update, err := fetch(ctx)
+
+	a.mu.Lock()
+	defer a.unlock()
+	if err == nil {
+		update()
+	}
+	f.err = err
+	delete(a.fills, key)
+	close(f.done)
+}
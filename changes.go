@@ -0,0 +1,51 @@
+package githubapp
+
+// ChangeType is the type of an InstallationChange.
+type ChangeType string
+
+// Types of installation changes.
+const (
+	ChangeInstalled           ChangeType = "installed"
+	ChangeUninstalled         ChangeType = "uninstalled"
+	ChangeSuspended           ChangeType = "suspended"
+	ChangeUnsuspended         ChangeType = "unsuspended"
+	ChangeRepositoriesAdded   ChangeType = "repositories_added"
+	ChangeRepositoriesRemoved ChangeType = "repositories_removed"
+)
+
+// InstallationChange describes a change to an installation of the App.
+type InstallationChange struct {
+	Type         ChangeType
+	Installation *Installation
+
+	// Repositories that were added or removed, for ChangeRepositoriesAdded and ChangeRepositoriesRemoved.
+	Repositories []string
+}
+
+// WithInstallationChangeHandler registers a function that is called with changes to the installations of the App. Changes
+// are detected when the cached installations and repositories are refreshed (the initial fetch is not reported), and when
+// webhook events are passed to UpdateFromEvent.
+func WithInstallationChangeHandler(f func(InstallationChange)) option {
+	return func(a *App) {
+		a.changeHandler = f
+	}
+}
+
+// notify queues a change to be passed to the change handler when a.mu is released with unlock. The caller must hold a.mu.
+func (a *App) notify(changeType ChangeType, i *installation, repositories ...string) {
+	if a.changeHandler == nil {
+		return
+	}
+	a.changes = append(a.changes, InstallationChange{Type: changeType, Installation: i.export(), Repositories: repositories})
+}
+
+// unlock releases a.mu and passes any queued changes to the change handler.
+func (a *App) unlock() {
+	changes := a.changes
+	a.changes = nil
+	a.mu.Unlock()
+
+	for _, c := range changes {
+		a.changeHandler(c)
+	}
+}
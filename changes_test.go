@@ -0,0 +1,123 @@
+package githubapp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestInstallationChanges(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		changes       []githubapp.InstallationChange
+		gh            = githubapp.New(client,
+			githubapp.WithInstallationClientFactory(clientFactory),
+			githubapp.WithUpdateInterval(0),
+			githubapp.WithInstallationChangeHandler(func(c githubapp.InstallationChange) {
+				changes = append(changes, c)
+			}),
+		)
+	)
+
+	client.ListInstallationsReturnsOnCall(0, []*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}},
+	}, &github.Response{}, nil)
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(3), Account: &github.User{Login: github.String("c")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	tokenClient.ListReposReturnsOnCall(0, &github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("x")}},
+	}, &github.Response{}, nil)
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(2), Name: github.String("y")}},
+	}, &github.Response{}, nil)
+
+	_, err := gh.Repositories("a")
+	noError(t, err)
+	isEqual(t, 0, len(changes))
+
+	_, err = gh.Repositories("a")
+	noError(t, err)
+
+	isEqual(t, []githubapp.InstallationChange{
+		{Type: githubapp.ChangeInstalled, Installation: &githubapp.Installation{ID: 3, Owner: "c"}},
+		{Type: githubapp.ChangeUninstalled, Installation: &githubapp.Installation{ID: 2, Owner: "b"}},
+		{Type: githubapp.ChangeRepositoriesAdded, Installation: &githubapp.Installation{ID: 1, Owner: "a"}, Repositories: []string{"y"}},
+		{Type: githubapp.ChangeRepositoriesRemoved, Installation: &githubapp.Installation{ID: 1, Owner: "a"}, Repositories: []string{"x"}},
+	}, changes)
+
+	changes = nil
+	gh.UpdateFromEvent(&github.InstallationEvent{
+		Action:       github.String("suspend"),
+		Installation: &github.Installation{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+	})
+	isEqual(t, []githubapp.InstallationChange{
+		{Type: githubapp.ChangeSuspended, Installation: &githubapp.Installation{ID: 1, Owner: "a", Suspended: true}},
+	}, changes)
+}
+
+func TestInstallationChangesOrder(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		changes       []githubapp.InstallationChange
+		gh            = githubapp.New(client,
+			githubapp.WithInstallationClientFactory(clientFactory),
+			githubapp.WithUpdateInterval(0),
+			githubapp.WithInstallationChangeHandler(func(c githubapp.InstallationChange) {
+				changes = append(changes, c)
+			}),
+		)
+	)
+
+	client.ListInstallationsReturnsOnCall(0, []*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+	}, &github.Response{}, nil)
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("a")}},
+		{ID: github.Int64(4), Account: &github.User{Login: github.String("d")}},
+		{ID: github.Int64(3), Account: &github.User{Login: github.String("c")}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("b")}},
+	}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	repositories := func(names ...string) *github.ListRepositories {
+		list := &github.ListRepositories{}
+		for i, name := range names {
+			list.Repositories = append(list.Repositories, &github.Repository{ID: github.Int64(int64(i)), Name: github.String(name)})
+		}
+		return list
+	}
+	tokenClient.ListReposReturnsOnCall(0, nil, nil, errors.New("failed"))
+	tokenClient.ListReposReturnsOnCall(1, repositories("z", "x"), &github.Response{}, nil)
+	tokenClient.ListReposReturns(repositories("z", "x", "w", "v"), &github.Response{}, nil)
+
+	// A failed listing does not leave a partial cache behind, so the next listing is not reported as added repositories.
+	_, err := gh.Repositories("a")
+	isEqual(t, "failed", err.Error())
+
+	for i := 0; i < 2; i++ {
+		_, err = gh.Repositories("a")
+		noError(t, err)
+	}
+
+	isEqual(t, []githubapp.InstallationChange{
+		{Type: githubapp.ChangeInstalled, Installation: &githubapp.Installation{ID: 2, Owner: "b"}},
+		{Type: githubapp.ChangeInstalled, Installation: &githubapp.Installation{ID: 3, Owner: "c"}},
+		{Type: githubapp.ChangeInstalled, Installation: &githubapp.Installation{ID: 4, Owner: "d"}},
+		{Type: githubapp.ChangeRepositoriesAdded, Installation: &githubapp.Installation{ID: 1, Owner: "a"}, Repositories: []string{"v", "w"}},
+	}, changes)
+}
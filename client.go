@@ -2,6 +2,7 @@ package githubapp
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/bradleyfalzon/ghinstallation"
@@ -12,14 +13,61 @@ import (
 
 // NewClient returns a client for the Github V3 (REST) AppsAPI authenticated with a private key.
 func NewClient(integrationID int64, privateKey []byte) (AppsJWTAPI, error) {
-	transport, err := ghinstallation.NewAppsTransport(http.DefaultTransport, integrationID, privateKey)
+	httpClient, err := NewJWTHTTPClient(integrationID, privateKey)
 	if err != nil {
 		return nil, err
 	}
-	client := github.NewClient(&http.Client{
-		Transport: transport,
-	})
-	return client.Apps, nil
+	return github.NewClient(httpClient).Apps, nil
+}
+
+// NewJWTHTTPClient returns a http.Client that authenticates requests as the App using a JWT signed with the private key. It can be
+// used to call App endpoints that are not covered by AppsJWTAPI (e.g. webhook deliveries).
+func NewJWTHTTPClient(integrationID int64, privateKey []byte) (*http.Client, error) {
+	return newJWTHTTPClient(http.DefaultTransport, integrationID, privateKey)
+}
+
+func newJWTHTTPClient(base http.RoundTripper, integrationID int64, privateKey []byte) (*http.Client, error) {
+	transport, err := ghinstallation.NewAppsTransport(base, integrationID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// ValidateCredentials checks that the private key belongs to the App with the given integration ID, by signing a JWT and
+// requesting the authenticated App. A rejected JWT is returned as ErrInvalidPrivateKey.
+func ValidateCredentials(ctx context.Context, integrationID int64, privateKey []byte) error {
+	return ValidateCredentialsWithTransport(ctx, nil, integrationID, privateKey)
+}
+
+// ValidateCredentialsWithTransport is like ValidateCredentials, but sends the request with the base transport (e.g. one returned
+// by NewEndpointTransport). If base is nil, http.DefaultTransport is used.
+func ValidateCredentialsWithTransport(ctx context.Context, base http.RoundTripper, integrationID int64, privateKey []byte) error {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient, err := newJWTHTTPClient(base, integrationID, privateKey)
+	if err != nil {
+		return err
+	}
+	app, response, err := github.NewClient(httpClient).Apps.Get(ctx, "")
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusUnauthorized {
+			return ErrInvalidPrivateKey(integrationID)
+		}
+		return err
+	}
+	if app.GetID() != integrationID {
+		return ErrInvalidPrivateKey(integrationID)
+	}
+	return nil
+}
+
+// ErrInvalidPrivateKey is returned if the private key is rejected by Github for the App integration ID.
+type ErrInvalidPrivateKey int64
+
+func (e ErrInvalidPrivateKey) Error() string {
+	return fmt.Sprintf("private key does not match app ID %d", int64(e))
 }
 
 // NewInstallationClient returns a new client.
@@ -27,6 +75,30 @@ func NewInstallationClient(token string) *InstallationClient {
 	client := oauth2.NewClient(context.TODO(), oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	))
+	return newInstallationClient(client)
+}
+
+// installationHTTPClient returns a http.Client that is authenticated with the installation token for the owner, and uses the
+// endpoint overrides, rate limits and installation concurrency limits of the App.
+func (a *App) installationHTTPClient(owner, token string) *http.Client {
+	return &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+			Base:   a.concurrency.transport(owner, a.transport(nil)),
+		},
+	}
+}
+
+// tokenClient returns an AppsTokenAPI client that is authenticated with the installation token for the owner, using the
+// InstallationClientFactory if one is set.
+func (a *App) tokenClient(owner, token string) AppsTokenAPI {
+	if a.installsClientFactory != nil {
+		return a.installsClientFactory(token)
+	}
+	return newInstallationClient(a.installationHTTPClient(owner, token)).V3.Apps
+}
+
+func newInstallationClient(client *http.Client) *InstallationClient {
 	return &InstallationClient{V3: github.NewClient(client), V4: githubv4.NewClient(client)}
 }
 
@@ -0,0 +1,84 @@
+package githubapp_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+)
+
+func TestJWTHTTPClient(t *testing.T) {
+	httpClient, err := githubapp.NewJWTHTTPClient(1, privateKey(t))
+	noError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("expected a JWT bearer token, got: '%s'", r.Header.Get("Authorization"))
+		}
+	}))
+	defer server.Close()
+
+	res, err := httpClient.Get(server.URL)
+	noError(t, err)
+	res.Body.Close()
+}
+
+func TestValidateCredentials(t *testing.T) {
+	tests := []struct {
+		description string
+		status      int
+		body        string
+		expected    error
+	}{
+		{
+			description: "valid credentials",
+			status:      http.StatusOK,
+			body:        `{"id":1}`,
+		},
+		{
+			description: "rejected private key",
+			status:      http.StatusUnauthorized,
+			body:        `{"message":"A JSON web token could not be decoded"}`,
+			expected:    githubapp.ErrInvalidPrivateKey(1),
+		},
+		{
+			description: "private key for another app",
+			status:      http.StatusOK,
+			body:        `{"id":2}`,
+			expected:    githubapp.ErrInvalidPrivateKey(1),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				isEqual(t, "/app", r.URL.Path)
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			target, err := url.Parse(server.URL)
+			noError(t, err)
+			transport := githubapp.NewEndpointTransport(nil, map[string]*url.URL{"api.github.com": target})
+
+			err = githubapp.ValidateCredentialsWithTransport(context.TODO(), transport, 1, privateKey(t))
+			isEqual(t, tc.expected, err)
+		})
+	}
+}
+
+// privateKey returns a new PEM encoded RSA private key.
+func privateKey(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	noError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
@@ -0,0 +1,72 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// githubHost is the host that git remotes are served from.
+const githubHost = "github.com"
+
+// ExpiringURL is a URL with embedded installation credentials, which are only valid until it expires.
+type ExpiringURL struct {
+	// URL includes the installation token, and must not be logged.
+	URL string
+
+	// Display is the same URL without credentials, and is safe to log or show to users.
+	Display string
+
+	// ExpiresAt is when the embedded token expires or is revoked.
+	ExpiresAt time.Time
+}
+
+// String returns the display form of the URL, so that the credentials are not leaked if it is printed by accident.
+func (u *ExpiringURL) String() string {
+	return u.Display
+}
+
+// WithFreshCloneURLs makes CloneURL create a new installation token on each call, instead of reusing the token for a
+// repository until it is about to expire.
+func WithFreshCloneURLs() option {
+	return func(a *App) {
+		a.freshCloneURLs = true
+	}
+}
+
+// CloneURL returns an HTTPS remote URL for the repository (https://x-access-token:<token>@github.com/owner/repo.git), for tools
+// that only accept a remote URL. The token is scoped to the repository, with the permissions of the installation.
+func (a *App) CloneURL(ctx context.Context, owner, repo string) (*ExpiringURL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key := owner + "/" + repo
+	a.mu.Lock()
+	token, ok := a.cloneTokens[key]
+	a.unlock()
+
+	if !ok || a.freshCloneURLs || time.Until(token.GetExpiresAt()) <= a.expiryMargin() {
+		var err error
+		if token, err = a.createInstallationToken(ctx, owner, []string{repo}, nil); err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		if a.cloneTokens == nil {
+			a.cloneTokens = make(map[string]*Token)
+		}
+		a.cloneTokens[key] = token
+		a.unlock()
+	}
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   githubHost,
+		Path:   fmt.Sprintf("/%s/%s.git", owner, repo),
+	}
+	display := u.String()
+	u.User = url.UserPassword("x-access-token", token.GetToken())
+
+	return &ExpiringURL{URL: u.String(), Display: display, ExpiresAt: token.GetExpiresAt()}, nil
+}
@@ -0,0 +1,95 @@
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithInstallationConcurrency limits the number of concurrent requests made on behalf of each installation, so that heavy use
+// of one installation cannot starve the others sharing the App. It applies to creating installation tokens, and to requests
+// made with installation clients and transports returned by the App. Requests wait for a slot until their context is done.
+// If n is zero or negative, the number of concurrent requests is not limited.
+func WithInstallationConcurrency(n int) option {
+	return func(a *App) {
+		if n <= 0 {
+			a.concurrency = nil
+			return
+		}
+		a.concurrency = &concurrencyLimiter{limit: n, slots: make(map[string]chan struct{})}
+	}
+}
+
+// concurrencyLimiter limits concurrent requests per owner. A nil concurrencyLimiter does not limit.
+type concurrencyLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// acquire waits for a slot for the owner, and returns a function that releases it.
+func (l *concurrencyLimiter) acquire(ctx context.Context, owner string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	slots, ok := l.slots[strings.ToLower(owner)]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[strings.ToLower(owner)] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-slots }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// transport wraps base so that requests hold a slot for the owner until the response body is closed. If the
+// concurrencyLimiter is nil, base is returned as is.
+func (l *concurrencyLimiter) transport(owner string, base http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return base
+	}
+	return &concurrencyTransport{limiter: l, owner: owner, base: base}
+}
+
+type concurrencyTransport struct {
+	limiter *concurrencyLimiter
+	owner   string
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context(), t.owner)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	res.Body = &releaseBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releaseBody releases the slot of a request when the response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements io.Closer.
+func (b *releaseBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,46 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type contextKey int
+
+const (
+	appContextKey contextKey = iota
+	installationContextKey
+	clientContextKey
+)
+
+// NewContext returns a copy of ctx that carries the App.
+func NewContext(ctx context.Context, app *App) context.Context {
+	return context.WithValue(ctx, appContextKey, app)
+}
+
+// FromContext returns the App stored in ctx, if any.
+func FromContext(ctx context.Context) (*App, bool) {
+	app, ok := ctx.Value(appContextKey).(*App)
+	return app, ok
+}
+
+// NewInstallationContext returns a copy of ctx that carries the installation.
+func NewInstallationContext(ctx context.Context, installation *Installation) context.Context {
+	return context.WithValue(ctx, installationContextKey, installation)
+}
+
+// InstallationFromContext returns the installation stored in ctx, if any.
+func InstallationFromContext(ctx context.Context) (*Installation, bool) {
+	installation, ok := ctx.Value(installationContextKey).(*Installation)
+	return installation, ok
+}
+
+// OwnerFromContext is an OwnerFunc that uses the owner of the installation stored in the request context.
+func OwnerFromContext(req *http.Request) (string, error) {
+	installation, ok := InstallationFromContext(req.Context())
+	if !ok {
+		return "", errors.New("no installation in request context")
+	}
+	return installation.Owner, nil
+}
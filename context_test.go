@@ -0,0 +1,38 @@
+package githubapp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestContext(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+		ctx    = githubapp.NewContext(context.Background(), gh)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	app, ok := githubapp.FromContext(ctx)
+	isEqual(t, true, ok)
+
+	installation, err := app.Installation("owner")
+	noError(t, err)
+	ctx = githubapp.NewInstallationContext(ctx, installation)
+
+	got, ok := githubapp.InstallationFromContext(ctx)
+	isEqual(t, true, ok)
+	isEqual(t, &githubapp.Installation{ID: 23, Owner: "owner"}, got)
+
+	_, ok = githubapp.InstallationFromContext(context.Background())
+	isEqual(t, false, ok)
+}
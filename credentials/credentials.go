@@ -0,0 +1,56 @@
+// Package credentials writes installation tokens into the configuration files of tools that cannot request them
+// directly, such as git, the Go toolchain and package managers, and keeps them up to date.
+package credentials
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/telia-oss/githubapp"
+)
+
+// Keep creates an installation token for the scope and passes it to write, and keeps passing a new token shortly
+// before the previous one expires, until the context is done.
+func Keep(ctx context.Context, app *githubapp.App, scope githubapp.Scope, write func(token string) error) error {
+	return app.WatchToken(ctx, scope, func(token *githubapp.Token) error {
+		return write(token.GetToken())
+	})
+}
+
+// property is a key and value in a file with one key=value pair per line.
+type property struct {
+	key   string
+	value string
+}
+
+// setProperties returns the content with the lines for the keys of the properties replaced, and any missing properties appended.
+func setProperties(content string, properties ...property) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	for _, p := range properties {
+		line := p.key + "=" + p.value
+		found := false
+		for i, l := range lines {
+			if k := strings.SplitN(l, "=", 2)[0]; strings.TrimSpace(k) == p.key {
+				lines[i], found = line, true
+			}
+		}
+		if !found {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// readFile returns the content of the file, or an empty string if it does not exist.
+func readFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(b), err
+}
@@ -0,0 +1,115 @@
+package credentials_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telia-oss/githubapp/credentials"
+)
+
+func isEqual(t *testing.T, expected, got interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nexpected:\n%v\n\ngot:\n%v", expected, got)
+	}
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+// writeTemp runs write against a temporary file with the given content, and returns the resulting content.
+func writeTemp(t *testing.T, content string, write func(path string) error) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "credentials")
+	noError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if content != "" {
+		noError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	noError(t, write(path))
+
+	b, err := ioutil.ReadFile(path)
+	noError(t, err)
+	return string(b)
+}
+
+func TestWriteNetrc(t *testing.T) {
+	tests := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "adds an entry to a new file",
+			input:       "",
+			expected:    "machine github.com login x-access-token password token\n",
+		},
+		{
+			description: "replaces an existing entry and keeps others",
+			input:       "machine github.com\n  login x-access-token\n  password old\nmachine example.com login user password secret\n",
+			expected:    "machine example.com login user password secret\nmachine github.com login x-access-token password token\n",
+		},
+		{
+			description: "does not replace other hosts",
+			input:       "machine github.company.com login user password secret\n",
+			expected:    "machine github.company.com login user password secret\nmachine github.com login x-access-token password token\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			isEqual(t, tc.expected, writeTemp(t, tc.input, func(path string) error {
+				return credentials.WriteNetrc(path, "token")
+			}))
+		})
+	}
+}
+
+func TestWriteNpmrc(t *testing.T) {
+	got := writeTemp(t, "always-auth=true\n//npm.pkg.github.com/:_authToken=old\n", func(path string) error {
+		return credentials.WriteNpmrc(path, "telia-oss", "token")
+	})
+	isEqual(t, "always-auth=true\n//npm.pkg.github.com/:_authToken=token\n@telia-oss:registry=https://npm.pkg.github.com\n", got)
+}
+
+func TestWriteMavenSettings(t *testing.T) {
+	got := writeTemp(t, "<settings/>", func(path string) error {
+		return credentials.WriteMavenSettings(path, "telia-oss", "token")
+	})
+	for _, s := range []string{
+		"<url>https://maven.pkg.github.com/telia-oss/*</url>",
+		"<password>token</password>",
+	} {
+		isEqual(t, true, strings.Contains(got, s))
+	}
+}
+
+func TestWriteGradleProperties(t *testing.T) {
+	got := writeTemp(t, "org.gradle.caching=true\ngpr.key=old\n", func(path string) error {
+		return credentials.WriteGradleProperties(path, "token")
+	})
+	isEqual(t, "org.gradle.caching=true\ngpr.key=token\ngpr.user=x-access-token\n", got)
+}
+
+func TestWriteNugetConfig(t *testing.T) {
+	got := writeTemp(t, "", func(path string) error {
+		return credentials.WriteNugetConfig(path, "telia-oss", "token")
+	})
+	for _, s := range []string{
+		`<add key="github" value="https://nuget.pkg.github.com/telia-oss/index.json" />`,
+		`<add key="ClearTextPassword" value="token" />`,
+	} {
+		isEqual(t, true, strings.Contains(got, s))
+	}
+}
@@ -0,0 +1,70 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/telia-oss/githubapp/internal/atomicfile"
+)
+
+const (
+	// mavenServerID is the ID of the server in settings.xml, which repositories in pom.xml must refer to.
+	mavenServerID = "github"
+
+	// mavenRegistry is the Maven registry for Github Packages.
+	mavenRegistry = "https://maven.pkg.github.com"
+)
+
+// mavenSettings is the settings.xml written by WriteMavenSettings.
+const mavenSettings = `<?xml version="1.0" encoding="UTF-8"?>
+<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0">
+  <activeProfiles>
+    <activeProfile>github</activeProfile>
+  </activeProfiles>
+  <profiles>
+    <profile>
+      <id>github</id>
+      <repositories>
+        <repository>
+          <id>%[1]s</id>
+          <url>%[2]s/%[3]s/*</url>
+        </repository>
+      </repositories>
+    </profile>
+  </profiles>
+  <servers>
+    <server>
+      <id>%[1]s</id>
+      <username>x-access-token</username>
+      <password>%[4]s</password>
+    </server>
+  </servers>
+</settings>
+`
+
+// WriteMavenSettings writes a Maven settings.xml to path which adds the Github Packages registry for the owner as a
+// repository, and authenticates against it with the installation token. Unlike the other writers, the file is replaced
+// as a whole, so it should not be pointed at a settings.xml that is maintained by hand.
+func WriteMavenSettings(path, owner, token string) error {
+	return atomicfile.Write(path, fmt.Sprintf(mavenSettings, mavenServerID, mavenRegistry, escapeXML(owner), escapeXML(token)))
+}
+
+// WriteGradleProperties sets the gpr.user and gpr.key properties in the gradle.properties file at path, which
+// repositories for Github Packages in build.gradle can use as credentials. Other properties are kept as is.
+func WriteGradleProperties(path, token string) error {
+	content, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, setProperties(content,
+		property{key: "gpr.user", value: "x-access-token"},
+		property{key: "gpr.key", value: token},
+	))
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package credentials
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/telia-oss/githubapp/internal/atomicfile"
+)
+
+var (
+	netrcMachine = regexp.MustCompile(`machine\s+github\.com(\s|$)`)
+	netrcEntry   = regexp.MustCompile(`(^|\s)(machine|default)(\s|$)`)
+)
+
+// WriteNetrc adds or replaces the entry for github.com in the netrc file at path, so that git and the Go toolchain
+// authenticate with the installation token. Other entries in the file are kept as is.
+func WriteNetrc(path, token string) error {
+	content, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, setNetrc(content, token))
+}
+
+// setNetrc returns the netrc content with the github.com entry replaced.
+func setNetrc(content, token string) string {
+	if loc := netrcMachine.FindStringIndex(content); loc != nil {
+		end := len(content)
+		if next := netrcEntry.FindStringIndex(content[loc[1]:]); next != nil {
+			end = loc[1] + next[0]
+		}
+		content = content[:loc[0]] + content[end:]
+	}
+	content = strings.TrimSpace(content)
+	if content != "" {
+		content += "\n"
+	}
+	return content + fmt.Sprintf("machine github.com login x-access-token password %s\n", token)
+}
+
+// GoEnv returns the environment variables that make the Go toolchain treat modules owned by the owner as private, so
+// that they are fetched directly from Github (using the credentials from WriteNetrc) instead of through the module proxy
+// and checksum database.
+func GoEnv(owner string) []string {
+	pattern := fmt.Sprintf("github.com/%s/*", owner)
+	return []string{
+		"GOPRIVATE=" + pattern,
+		"GONOSUMDB=" + pattern,
+		"GONOPROXY=" + pattern,
+	}
+}
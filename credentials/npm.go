@@ -0,0 +1,19 @@
+package credentials
+
+import "github.com/telia-oss/githubapp/internal/atomicfile"
+
+// npmRegistry is the npm registry for Github Packages.
+const npmRegistry = "https://npm.pkg.github.com"
+
+// WriteNpmrc sets the auth token for Github Packages in the .npmrc at path, and makes it the registry for packages in the
+// owner's scope (e.g. @telia-oss). Other settings in the file are kept as is.
+func WriteNpmrc(path, owner, token string) error {
+	content, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, setProperties(content,
+		property{key: "@" + owner + ":registry", value: npmRegistry},
+		property{key: "//npm.pkg.github.com/:_authToken", value: token},
+	))
+}
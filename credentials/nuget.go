@@ -0,0 +1,31 @@
+package credentials
+
+import (
+	"fmt"
+
+	"github.com/telia-oss/githubapp/internal/atomicfile"
+)
+
+// nugetRegistry is the NuGet registry for Github Packages.
+const nugetRegistry = "https://nuget.pkg.github.com"
+
+// nugetConfig is the NuGet.config written by WriteNugetConfig.
+const nugetConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="github" value="%[1]s/%[2]s/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <github>
+      <add key="Username" value="x-access-token" />
+      <add key="ClearTextPassword" value="%[3]s" />
+    </github>
+  </packageSourceCredentials>
+</configuration>
+`
+
+// WriteNugetConfig writes a NuGet.config to path which adds the Github Packages feed for the owner as a package source,
+// and authenticates against it with the installation token. Like WriteMavenSettings, the file is replaced as a whole.
+func WriteNugetConfig(path, owner, token string) error {
+	return atomicfile.Write(path, fmt.Sprintf(nugetConfig, nugetRegistry, escapeXML(owner), escapeXML(token)))
+}
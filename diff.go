@@ -0,0 +1,104 @@
+package githubapp
+
+import (
+	"context"
+	"sort"
+)
+
+// InstallationDiff describes how the installation To differs from the installation From.
+type InstallationDiff struct {
+	From string
+	To   string
+
+	// Permissions that are granted with a different access level (an empty level means that the permission is not granted).
+	Permissions []PermissionDiff
+
+	// AddedEvents are subscribed to by To but not From, and RemovedEvents the other way around.
+	AddedEvents   []string
+	RemovedEvents []string
+
+	// RepositorySelection is set if the installations differ in whether all or only selected repositories are available.
+	RepositorySelection *RepositorySelectionDiff
+}
+
+// PermissionDiff describes a permission that has different access levels in two installations.
+type PermissionDiff struct {
+	Permission string
+	From       string
+	To         string
+}
+
+// RepositorySelectionDiff describes the repository selection of two installations.
+type RepositorySelectionDiff struct {
+	From string
+	To   string
+}
+
+// Empty returns true if there are no differences between the installations.
+func (d *InstallationDiff) Empty() bool {
+	return len(d.Permissions) == 0 && len(d.AddedEvents) == 0 && len(d.RemovedEvents) == 0 && d.RepositorySelection == nil
+}
+
+// CompareInstallations returns the differences in granted permissions, subscribed events and repository selection
+// between the installations for the two owners.
+func (a *App) CompareInstallations(from, to string) (*InstallationDiff, error) {
+	return a.CompareInstallationsContext(context.Background(), from, to)
+}
+
+// CompareInstallationsContext is like CompareInstallations, but uses the context for requests made to refresh the cache.
+func (a *App) CompareInstallationsContext(ctx context.Context, from, to string) (*InstallationDiff, error) {
+	f, err := a.InstallationContext(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := a.InstallationContext(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffInstallations(f, t), nil
+}
+
+// DiffInstallations returns the differences between the two installations.
+func DiffInstallations(from, to *Installation) *InstallationDiff {
+	diff := &InstallationDiff{From: from.Owner, To: to.Owner}
+
+	diff.Permissions = Diff(from.Permissions, to.Permissions)
+	diff.AddedEvents = difference(to.Events, from.Events)
+	diff.RemovedEvents = difference(from.Events, to.Events)
+
+	if from.RepositorySelection != to.RepositorySelection {
+		diff.RepositorySelection = &RepositorySelectionDiff{From: from.RepositorySelection, To: to.RepositorySelection}
+	}
+	return diff
+}
+
+// unionKeys returns the sorted keys that are present in either map.
+func unionKeys(a, b map[string]string) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// difference returns the sorted elements of a that are not in b.
+func difference(a, b []string) []string {
+	seen := make(map[string]bool, len(b))
+	for _, s := range b {
+		seen[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !seen[s] {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
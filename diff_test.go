@@ -0,0 +1,64 @@
+package githubapp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestDiffInstallations(t *testing.T) {
+	staging := &githubapp.Installation{
+		Owner:               "staging",
+		RepositorySelection: "all",
+		Permissions: &githubapp.Permissions{
+			Contents: github.String("write"),
+			Metadata: github.String("read"),
+			Checks:   github.String("write"),
+		},
+		Events: []string{"push", "check_run"},
+	}
+	prod := &githubapp.Installation{
+		Owner:               "prod",
+		RepositorySelection: "selected",
+		Permissions: &githubapp.Permissions{
+			Contents: github.String("read"),
+			Metadata: github.String("read"),
+		},
+		Events: []string{"push", "pull_request"},
+	}
+
+	diff := githubapp.DiffInstallations(staging, prod)
+	isEqual(t, []githubapp.PermissionDiff{
+		{Permission: "checks", From: "write", To: ""},
+		{Permission: "contents", From: "write", To: "read"},
+	}, diff.Permissions)
+	isEqual(t, []string{"pull_request"}, diff.AddedEvents)
+	isEqual(t, []string{"check_run"}, diff.RemovedEvents)
+	isEqual(t, &githubapp.RepositorySelectionDiff{From: "all", To: "selected"}, diff.RepositorySelection)
+	isEqual(t, false, diff.Empty())
+	isEqual(t, true, githubapp.DiffInstallations(prod, prod).Empty())
+}
+
+func TestCompareInstallationsContext(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{
+		{ID: github.Int64(1), Account: &github.User{Login: github.String("staging")}, Events: []string{"push"}},
+		{ID: github.Int64(2), Account: &github.User{Login: github.String("prod")}},
+	}, &github.Response{}, nil)
+
+	type key struct{}
+	diff, err := gh.CompareInstallationsContext(context.WithValue(context.Background(), key{}, "value"), "staging", "prod")
+	noError(t, err)
+	isEqual(t, []string{"push"}, diff.RemovedEvents)
+
+	ctx, _ := client.ListInstallationsArgsForCall(0)
+	isEqual(t, "value", ctx.Value(key{}))
+}
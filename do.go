@@ -0,0 +1,15 @@
+package githubapp
+
+import (
+	"context"
+	"net/http"
+)
+
+// Do sends the request authenticated as the installation for the owner, and can be used for endpoints that are not covered
+// by go-github. Installation tokens are reused across calls until they are about to expire.
+func (a *App) Do(ctx context.Context, owner string, req *http.Request) (*http.Response, error) {
+	a.doOnce.Do(func() {
+		a.doClient = &http.Client{Transport: a.Transport(nil, OwnerFromContext)}
+	})
+	return a.doClient.Do(req.WithContext(NewInstallationContext(ctx, &Installation{Owner: owner})))
+}
@@ -0,0 +1,109 @@
+// Package e2etest provides helpers for running end-to-end tests against a real Github App installation.
+package e2etest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+)
+
+// Environment variables that are used to configure the tests.
+const (
+	EnvIntegrationID  = "GITHUB_APP_INTEGRATION_ID"
+	EnvPrivateKeyFile = "GITHUB_APP_PRIVATE_KEY_FILE"
+	EnvTargetOwner    = "GITHUB_APP_TARGET_ORG"
+	EnvTargetRepo     = "GITHUB_APP_TARGET_REPOSITORY"
+)
+
+// Config holds the credentials for the App and the installation that the tests should target.
+type Config struct {
+	IntegrationID int64
+	PrivateKey    []byte
+	Owner         string
+	Repository    string
+}
+
+// ConfigFromEnv reads the Config from the environment, and skips the test if any of the variables are unset.
+func ConfigFromEnv(t testing.TB) *Config {
+	t.Helper()
+
+	for _, key := range []string{EnvIntegrationID, EnvPrivateKeyFile, EnvTargetOwner, EnvTargetRepo} {
+		if os.Getenv(key) == "" {
+			t.Skipf("skipping e2e test: %s is not set", key)
+		}
+	}
+
+	integrationID, err := strconv.ParseInt(os.Getenv(EnvIntegrationID), 10, 64)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %s", EnvIntegrationID, err)
+	}
+	privateKey, err := ioutil.ReadFile(os.Getenv(EnvPrivateKeyFile))
+	if err != nil {
+		t.Fatalf("failed to read private key: %s", err)
+	}
+
+	return &Config{
+		IntegrationID: integrationID,
+		PrivateKey:    privateKey,
+		Owner:         os.Getenv(EnvTargetOwner),
+		Repository:    os.Getenv(EnvTargetRepo),
+	}
+}
+
+// App returns a new App that is authenticated with the credentials in the Config.
+func (c *Config) App(t testing.TB) *githubapp.App {
+	t.Helper()
+
+	client, err := githubapp.NewClient(c.IntegrationID, c.PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	return githubapp.New(client)
+}
+
+// Mint creates an installation token for the target owner, scoped to the repositories and permissions.
+func (c *Config) Mint(t testing.TB, app *githubapp.App, repositories []string, permissions *githubapp.Permissions) *githubapp.Token {
+	t.Helper()
+
+	token, err := app.CreateInstallationToken(c.Owner, repositories, permissions)
+	if err != nil {
+		t.Fatalf("failed to create installation token: %s", err)
+	}
+	return token
+}
+
+// VerifyScope fails the test if the token does not grant exactly the given permissions, or grants access to other repositories.
+func VerifyScope(t testing.TB, token *githubapp.Token, repositories []string, permissions *githubapp.Permissions) {
+	t.Helper()
+
+	diff := githubapp.DiffInstallations(
+		&githubapp.Installation{Permissions: permissions},
+		&githubapp.Installation{Permissions: (*githubapp.Permissions)(token.Permissions)},
+	)
+	for _, p := range diff.Permissions {
+		t.Errorf("permission %s: expected '%s', got '%s'", p.Permission, p.From, p.To)
+	}
+
+	allowed := make(map[string]bool, len(repositories))
+	for _, r := range repositories {
+		allowed[r] = true
+	}
+	for _, r := range token.Repositories {
+		if !allowed[r.GetName()] {
+			t.Errorf("unexpected repository in token scope: %s", r.GetName())
+		}
+	}
+}
+
+// Revoke revokes the installation token.
+func Revoke(t testing.TB, token *githubapp.Token) {
+	t.Helper()
+
+	if _, err := githubapp.NewInstallationClient(token.GetToken()).V3.Apps.RevokeInstallationToken(context.TODO()); err != nil {
+		t.Fatalf("failed to revoke installation token: %s", err)
+	}
+}
@@ -0,0 +1,59 @@
+package githubapp
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithEndpoint sends requests made by the App for the host (e.g. api.github.com) to the target URL instead, without changing the
+// request paths. It applies to the default installation clients and transports returned by the App, and can be used to point them
+// at a local fake in integration tests. Calling it multiple times adds an override per host.
+func WithEndpoint(host string, target *url.URL) option {
+	return func(a *App) {
+		if a.endpoints == nil {
+			a.endpoints = make(map[string]*url.URL)
+		}
+		a.endpoints[strings.ToLower(host)] = target
+	}
+}
+
+// NewEndpointTransport returns a http.RoundTripper that sends requests for the hosts in endpoints to the corresponding target URL
+// instead. Only the scheme and host of the target are used. If base is nil, http.DefaultTransport is used.
+func NewEndpointTransport(base http.RoundTripper, endpoints map[string]*url.URL) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	overrides := make(map[string]*url.URL, len(endpoints))
+	for host, target := range endpoints {
+		overrides[strings.ToLower(host)] = target
+	}
+	return &endpointTransport{base: base, endpoints: overrides}
+}
+
+type endpointTransport struct {
+	base      http.RoundTripper
+	endpoints map[string]*url.URL
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, ok := t.endpoints[strings.ToLower(req.URL.Host)]
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	r := req.Clone(req.Context())
+	r.URL.Scheme, r.URL.Host, r.Host = target.Scheme, target.Host, target.Host
+	return t.base.RoundTrip(r)
+}
+
+// transport wraps base with the endpoint overrides, faults and rate limits of the App. Rate limits are applied to the original host.
+func (a *App) transport(base http.RoundTripper) http.RoundTripper {
+	if a.faults != nil {
+		base = NewFaultTransport(base, *a.faults)
+	}
+	if len(a.endpoints) > 0 {
+		base = NewEndpointTransport(base, a.endpoints)
+	}
+	return a.limiter.transport(base)
+}
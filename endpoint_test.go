@@ -0,0 +1,42 @@
+package githubapp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestWithEndpoint(t *testing.T) {
+	var (
+		client    = &fakes.FakeAppsJWTAPI{}
+		expiresAt = time.Now().Add(1 * time.Hour)
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isEqual(t, "/installation/repositories", r.URL.Path)
+		isEqual(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"total_count":1,"repositories":[{"id":1,"name":"repository"}]}`))
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	noError(t, err)
+	gh := githubapp.New(client, githubapp.WithEndpoint("api.github.com", target))
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(1),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token"), ExpiresAt: &expiresAt}, nil, nil)
+
+	repositories, err := gh.Repositories("owner")
+	noError(t, err)
+	isEqual(t, []*githubapp.Repository{{ID: 1, Name: "repository"}}, repositories)
+}
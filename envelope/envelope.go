@@ -0,0 +1,90 @@
+// Package envelope seals installation tokens for relaying through intermediaries such as queues and files. Envelopes are
+// encrypted and authenticated with a shared key (AES-256-GCM), so recipients can verify that a token was sealed by a holder of
+// the key and has not expired before using it.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// KeySize is the size in bytes of the keys used to seal and open envelopes.
+const KeySize = 32
+
+// contents of a sealed envelope.
+type contents struct {
+	Token    *github.InstallationToken `json:"token"`
+	SealedAt time.Time                 `json:"sealed_at"`
+}
+
+// Seal encrypts the token with the key, and returns the envelope as a URL safe string.
+func Seal(key []byte, token *githubapp.Token) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := json.Marshal(contents{Token: token.InstallationToken, SealedAt: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %s", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Open decrypts an envelope created by Seal with the same key, and returns the token along with the time it was sealed. An
+// envelope which was not sealed with the key, or has been tampered with, returns ErrInvalidEnvelope. An envelope with an expired
+// token returns ErrTokenExpired.
+func Open(key []byte, envelope string) (*githubapp.Token, time.Time, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(envelope)
+	if err != nil || len(b) < aead.NonceSize() {
+		return nil, time.Time{}, ErrInvalidEnvelope
+	}
+	plaintext, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, time.Time{}, ErrInvalidEnvelope
+	}
+	var c contents
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return nil, time.Time{}, ErrInvalidEnvelope
+	}
+	if !c.Token.GetExpiresAt().After(time.Now()) {
+		return nil, c.SealedAt, ErrTokenExpired
+	}
+	return &githubapp.Token{InstallationToken: c.Token}, c.SealedAt, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size: expected %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	// ErrInvalidEnvelope is returned by Open if the envelope cannot be decrypted with the key.
+	ErrInvalidEnvelope = errors.New("invalid envelope")
+
+	// ErrTokenExpired is returned by Open if the token in the envelope has expired.
+	ErrTokenExpired = errors.New("token in envelope has expired")
+)
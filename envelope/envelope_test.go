@@ -0,0 +1,60 @@
+package envelope_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/envelope"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func newToken(expiresAt time.Time) *githubapp.Token {
+	return &githubapp.Token{InstallationToken: &github.InstallationToken{
+		Token:     github.String("token"),
+		ExpiresAt: &expiresAt,
+	}}
+}
+
+func TestEnvelope(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, envelope.KeySize)
+
+	sealed, err := envelope.Seal(key, newToken(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	token, sealedAt, err := envelope.Open(key, sealed)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if token.GetToken() != "token" {
+		t.Errorf("expected token, got %s", token.GetToken())
+	}
+	if time.Since(sealedAt) > time.Minute {
+		t.Errorf("unexpected seal time: %s", sealedAt)
+	}
+
+	if _, _, err := envelope.Open(bytes.Repeat([]byte{2}, envelope.KeySize), sealed); err != envelope.ErrInvalidEnvelope {
+		t.Errorf("expected ErrInvalidEnvelope for the wrong key, got %v", err)
+	}
+	modified := []byte(sealed)
+	modified[len(modified)/2] ^= 1
+	if _, _, err := envelope.Open(key, string(modified)); err != envelope.ErrInvalidEnvelope {
+		t.Errorf("expected ErrInvalidEnvelope for a modified envelope, got %v", err)
+	}
+}
+
+func TestEnvelopeExpired(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, envelope.KeySize)
+
+	sealed, err := envelope.Seal(key, newToken(time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err := envelope.Open(key, sealed); err != envelope.ErrTokenExpired {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
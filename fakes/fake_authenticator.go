@@ -0,0 +1,687 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/telia-oss/githubapp"
+)
+
+type FakeAuthenticator struct {
+	CreateInstallationTokenStub        func(string, []string, *githubapp.Permissions) (*githubapp.Token, error)
+	createInstallationTokenMutex       sync.RWMutex
+	createInstallationTokenArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 *githubapp.Permissions
+	}
+	createInstallationTokenReturns struct {
+		result1 *githubapp.Token
+		result2 error
+	}
+	createInstallationTokenReturnsOnCall map[int]struct {
+		result1 *githubapp.Token
+		result2 error
+	}
+	CreateInstallationTokenContextStub        func(context.Context, string, []string, *githubapp.Permissions) (*githubapp.Token, error)
+	createInstallationTokenContextMutex       sync.RWMutex
+	createInstallationTokenContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 *githubapp.Permissions
+	}
+	createInstallationTokenContextReturns struct {
+		result1 *githubapp.Token
+		result2 error
+	}
+	createInstallationTokenContextReturnsOnCall map[int]struct {
+		result1 *githubapp.Token
+		result2 error
+	}
+	InstallationClientStub        func(string, []string, *githubapp.Permissions) (*githubapp.InstallationClient, error)
+	installationClientMutex       sync.RWMutex
+	installationClientArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 *githubapp.Permissions
+	}
+	installationClientReturns struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}
+	installationClientReturnsOnCall map[int]struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}
+	InstallationClientContextStub        func(context.Context, string, []string, *githubapp.Permissions) (*githubapp.InstallationClient, error)
+	installationClientContextMutex       sync.RWMutex
+	installationClientContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 *githubapp.Permissions
+	}
+	installationClientContextReturns struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}
+	installationClientContextReturnsOnCall map[int]struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}
+	InstallationsStub        func() ([]*githubapp.Installation, error)
+	installationsMutex       sync.RWMutex
+	installationsArgsForCall []struct {
+	}
+	installationsReturns struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}
+	installationsReturnsOnCall map[int]struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}
+	InstallationsContextStub        func(context.Context) ([]*githubapp.Installation, error)
+	installationsContextMutex       sync.RWMutex
+	installationsContextArgsForCall []struct {
+		arg1 context.Context
+	}
+	installationsContextReturns struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}
+	installationsContextReturnsOnCall map[int]struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}
+	RepositoriesStub        func(string) ([]*githubapp.Repository, error)
+	repositoriesMutex       sync.RWMutex
+	repositoriesArgsForCall []struct {
+		arg1 string
+	}
+	repositoriesReturns struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}
+	repositoriesReturnsOnCall map[int]struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}
+	RepositoriesContextStub        func(context.Context, string) ([]*githubapp.Repository, error)
+	repositoriesContextMutex       sync.RWMutex
+	repositoriesContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	repositoriesContextReturns struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}
+	repositoriesContextReturnsOnCall map[int]struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAuthenticator) CreateInstallationToken(arg1 string, arg2 []string, arg3 *githubapp.Permissions) (*githubapp.Token, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.createInstallationTokenMutex.Lock()
+	ret, specificReturn := fake.createInstallationTokenReturnsOnCall[len(fake.createInstallationTokenArgsForCall)]
+	fake.createInstallationTokenArgsForCall = append(fake.createInstallationTokenArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 *githubapp.Permissions
+	}{arg1, arg2Copy, arg3})
+	stub := fake.CreateInstallationTokenStub
+	fakeReturns := fake.createInstallationTokenReturns
+	fake.recordInvocation("CreateInstallationToken", []interface{}{arg1, arg2Copy, arg3})
+	fake.createInstallationTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenCallCount() int {
+	fake.createInstallationTokenMutex.RLock()
+	defer fake.createInstallationTokenMutex.RUnlock()
+	return len(fake.createInstallationTokenArgsForCall)
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenCalls(stub func(string, []string, *githubapp.Permissions) (*githubapp.Token, error)) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = stub
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenArgsForCall(i int) (string, []string, *githubapp.Permissions) {
+	fake.createInstallationTokenMutex.RLock()
+	defer fake.createInstallationTokenMutex.RUnlock()
+	argsForCall := fake.createInstallationTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenReturns(result1 *githubapp.Token, result2 error) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = nil
+	fake.createInstallationTokenReturns = struct {
+		result1 *githubapp.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenReturnsOnCall(i int, result1 *githubapp.Token, result2 error) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = nil
+	if fake.createInstallationTokenReturnsOnCall == nil {
+		fake.createInstallationTokenReturnsOnCall = make(map[int]struct {
+			result1 *githubapp.Token
+			result2 error
+		})
+	}
+	fake.createInstallationTokenReturnsOnCall[i] = struct {
+		result1 *githubapp.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContext(arg1 context.Context, arg2 string, arg3 []string, arg4 *githubapp.Permissions) (*githubapp.Token, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.createInstallationTokenContextMutex.Lock()
+	ret, specificReturn := fake.createInstallationTokenContextReturnsOnCall[len(fake.createInstallationTokenContextArgsForCall)]
+	fake.createInstallationTokenContextArgsForCall = append(fake.createInstallationTokenContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 *githubapp.Permissions
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.CreateInstallationTokenContextStub
+	fakeReturns := fake.createInstallationTokenContextReturns
+	fake.recordInvocation("CreateInstallationTokenContext", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.createInstallationTokenContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContextCallCount() int {
+	fake.createInstallationTokenContextMutex.RLock()
+	defer fake.createInstallationTokenContextMutex.RUnlock()
+	return len(fake.createInstallationTokenContextArgsForCall)
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContextCalls(stub func(context.Context, string, []string, *githubapp.Permissions) (*githubapp.Token, error)) {
+	fake.createInstallationTokenContextMutex.Lock()
+	defer fake.createInstallationTokenContextMutex.Unlock()
+	fake.CreateInstallationTokenContextStub = stub
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContextArgsForCall(i int) (context.Context, string, []string, *githubapp.Permissions) {
+	fake.createInstallationTokenContextMutex.RLock()
+	defer fake.createInstallationTokenContextMutex.RUnlock()
+	argsForCall := fake.createInstallationTokenContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContextReturns(result1 *githubapp.Token, result2 error) {
+	fake.createInstallationTokenContextMutex.Lock()
+	defer fake.createInstallationTokenContextMutex.Unlock()
+	fake.CreateInstallationTokenContextStub = nil
+	fake.createInstallationTokenContextReturns = struct {
+		result1 *githubapp.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) CreateInstallationTokenContextReturnsOnCall(i int, result1 *githubapp.Token, result2 error) {
+	fake.createInstallationTokenContextMutex.Lock()
+	defer fake.createInstallationTokenContextMutex.Unlock()
+	fake.CreateInstallationTokenContextStub = nil
+	if fake.createInstallationTokenContextReturnsOnCall == nil {
+		fake.createInstallationTokenContextReturnsOnCall = make(map[int]struct {
+			result1 *githubapp.Token
+			result2 error
+		})
+	}
+	fake.createInstallationTokenContextReturnsOnCall[i] = struct {
+		result1 *githubapp.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationClient(arg1 string, arg2 []string, arg3 *githubapp.Permissions) (*githubapp.InstallationClient, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.installationClientMutex.Lock()
+	ret, specificReturn := fake.installationClientReturnsOnCall[len(fake.installationClientArgsForCall)]
+	fake.installationClientArgsForCall = append(fake.installationClientArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 *githubapp.Permissions
+	}{arg1, arg2Copy, arg3})
+	stub := fake.InstallationClientStub
+	fakeReturns := fake.installationClientReturns
+	fake.recordInvocation("InstallationClient", []interface{}{arg1, arg2Copy, arg3})
+	fake.installationClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) InstallationClientCallCount() int {
+	fake.installationClientMutex.RLock()
+	defer fake.installationClientMutex.RUnlock()
+	return len(fake.installationClientArgsForCall)
+}
+
+func (fake *FakeAuthenticator) InstallationClientCalls(stub func(string, []string, *githubapp.Permissions) (*githubapp.InstallationClient, error)) {
+	fake.installationClientMutex.Lock()
+	defer fake.installationClientMutex.Unlock()
+	fake.InstallationClientStub = stub
+}
+
+func (fake *FakeAuthenticator) InstallationClientArgsForCall(i int) (string, []string, *githubapp.Permissions) {
+	fake.installationClientMutex.RLock()
+	defer fake.installationClientMutex.RUnlock()
+	argsForCall := fake.installationClientArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAuthenticator) InstallationClientReturns(result1 *githubapp.InstallationClient, result2 error) {
+	fake.installationClientMutex.Lock()
+	defer fake.installationClientMutex.Unlock()
+	fake.InstallationClientStub = nil
+	fake.installationClientReturns = struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationClientReturnsOnCall(i int, result1 *githubapp.InstallationClient, result2 error) {
+	fake.installationClientMutex.Lock()
+	defer fake.installationClientMutex.Unlock()
+	fake.InstallationClientStub = nil
+	if fake.installationClientReturnsOnCall == nil {
+		fake.installationClientReturnsOnCall = make(map[int]struct {
+			result1 *githubapp.InstallationClient
+			result2 error
+		})
+	}
+	fake.installationClientReturnsOnCall[i] = struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationClientContext(arg1 context.Context, arg2 string, arg3 []string, arg4 *githubapp.Permissions) (*githubapp.InstallationClient, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.installationClientContextMutex.Lock()
+	ret, specificReturn := fake.installationClientContextReturnsOnCall[len(fake.installationClientContextArgsForCall)]
+	fake.installationClientContextArgsForCall = append(fake.installationClientContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 *githubapp.Permissions
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.InstallationClientContextStub
+	fakeReturns := fake.installationClientContextReturns
+	fake.recordInvocation("InstallationClientContext", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.installationClientContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) InstallationClientContextCallCount() int {
+	fake.installationClientContextMutex.RLock()
+	defer fake.installationClientContextMutex.RUnlock()
+	return len(fake.installationClientContextArgsForCall)
+}
+
+func (fake *FakeAuthenticator) InstallationClientContextCalls(stub func(context.Context, string, []string, *githubapp.Permissions) (*githubapp.InstallationClient, error)) {
+	fake.installationClientContextMutex.Lock()
+	defer fake.installationClientContextMutex.Unlock()
+	fake.InstallationClientContextStub = stub
+}
+
+func (fake *FakeAuthenticator) InstallationClientContextArgsForCall(i int) (context.Context, string, []string, *githubapp.Permissions) {
+	fake.installationClientContextMutex.RLock()
+	defer fake.installationClientContextMutex.RUnlock()
+	argsForCall := fake.installationClientContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAuthenticator) InstallationClientContextReturns(result1 *githubapp.InstallationClient, result2 error) {
+	fake.installationClientContextMutex.Lock()
+	defer fake.installationClientContextMutex.Unlock()
+	fake.InstallationClientContextStub = nil
+	fake.installationClientContextReturns = struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationClientContextReturnsOnCall(i int, result1 *githubapp.InstallationClient, result2 error) {
+	fake.installationClientContextMutex.Lock()
+	defer fake.installationClientContextMutex.Unlock()
+	fake.InstallationClientContextStub = nil
+	if fake.installationClientContextReturnsOnCall == nil {
+		fake.installationClientContextReturnsOnCall = make(map[int]struct {
+			result1 *githubapp.InstallationClient
+			result2 error
+		})
+	}
+	fake.installationClientContextReturnsOnCall[i] = struct {
+		result1 *githubapp.InstallationClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) Installations() ([]*githubapp.Installation, error) {
+	fake.installationsMutex.Lock()
+	ret, specificReturn := fake.installationsReturnsOnCall[len(fake.installationsArgsForCall)]
+	fake.installationsArgsForCall = append(fake.installationsArgsForCall, struct {
+	}{})
+	stub := fake.InstallationsStub
+	fakeReturns := fake.installationsReturns
+	fake.recordInvocation("Installations", []interface{}{})
+	fake.installationsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) InstallationsCallCount() int {
+	fake.installationsMutex.RLock()
+	defer fake.installationsMutex.RUnlock()
+	return len(fake.installationsArgsForCall)
+}
+
+func (fake *FakeAuthenticator) InstallationsCalls(stub func() ([]*githubapp.Installation, error)) {
+	fake.installationsMutex.Lock()
+	defer fake.installationsMutex.Unlock()
+	fake.InstallationsStub = stub
+}
+
+func (fake *FakeAuthenticator) InstallationsReturns(result1 []*githubapp.Installation, result2 error) {
+	fake.installationsMutex.Lock()
+	defer fake.installationsMutex.Unlock()
+	fake.InstallationsStub = nil
+	fake.installationsReturns = struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationsReturnsOnCall(i int, result1 []*githubapp.Installation, result2 error) {
+	fake.installationsMutex.Lock()
+	defer fake.installationsMutex.Unlock()
+	fake.InstallationsStub = nil
+	if fake.installationsReturnsOnCall == nil {
+		fake.installationsReturnsOnCall = make(map[int]struct {
+			result1 []*githubapp.Installation
+			result2 error
+		})
+	}
+	fake.installationsReturnsOnCall[i] = struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationsContext(arg1 context.Context) ([]*githubapp.Installation, error) {
+	fake.installationsContextMutex.Lock()
+	ret, specificReturn := fake.installationsContextReturnsOnCall[len(fake.installationsContextArgsForCall)]
+	fake.installationsContextArgsForCall = append(fake.installationsContextArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.InstallationsContextStub
+	fakeReturns := fake.installationsContextReturns
+	fake.recordInvocation("InstallationsContext", []interface{}{arg1})
+	fake.installationsContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) InstallationsContextCallCount() int {
+	fake.installationsContextMutex.RLock()
+	defer fake.installationsContextMutex.RUnlock()
+	return len(fake.installationsContextArgsForCall)
+}
+
+func (fake *FakeAuthenticator) InstallationsContextCalls(stub func(context.Context) ([]*githubapp.Installation, error)) {
+	fake.installationsContextMutex.Lock()
+	defer fake.installationsContextMutex.Unlock()
+	fake.InstallationsContextStub = stub
+}
+
+func (fake *FakeAuthenticator) InstallationsContextArgsForCall(i int) context.Context {
+	fake.installationsContextMutex.RLock()
+	defer fake.installationsContextMutex.RUnlock()
+	argsForCall := fake.installationsContextArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAuthenticator) InstallationsContextReturns(result1 []*githubapp.Installation, result2 error) {
+	fake.installationsContextMutex.Lock()
+	defer fake.installationsContextMutex.Unlock()
+	fake.InstallationsContextStub = nil
+	fake.installationsContextReturns = struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) InstallationsContextReturnsOnCall(i int, result1 []*githubapp.Installation, result2 error) {
+	fake.installationsContextMutex.Lock()
+	defer fake.installationsContextMutex.Unlock()
+	fake.InstallationsContextStub = nil
+	if fake.installationsContextReturnsOnCall == nil {
+		fake.installationsContextReturnsOnCall = make(map[int]struct {
+			result1 []*githubapp.Installation
+			result2 error
+		})
+	}
+	fake.installationsContextReturnsOnCall[i] = struct {
+		result1 []*githubapp.Installation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) Repositories(arg1 string) ([]*githubapp.Repository, error) {
+	fake.repositoriesMutex.Lock()
+	ret, specificReturn := fake.repositoriesReturnsOnCall[len(fake.repositoriesArgsForCall)]
+	fake.repositoriesArgsForCall = append(fake.repositoriesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RepositoriesStub
+	fakeReturns := fake.repositoriesReturns
+	fake.recordInvocation("Repositories", []interface{}{arg1})
+	fake.repositoriesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) RepositoriesCallCount() int {
+	fake.repositoriesMutex.RLock()
+	defer fake.repositoriesMutex.RUnlock()
+	return len(fake.repositoriesArgsForCall)
+}
+
+func (fake *FakeAuthenticator) RepositoriesCalls(stub func(string) ([]*githubapp.Repository, error)) {
+	fake.repositoriesMutex.Lock()
+	defer fake.repositoriesMutex.Unlock()
+	fake.RepositoriesStub = stub
+}
+
+func (fake *FakeAuthenticator) RepositoriesArgsForCall(i int) string {
+	fake.repositoriesMutex.RLock()
+	defer fake.repositoriesMutex.RUnlock()
+	argsForCall := fake.repositoriesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAuthenticator) RepositoriesReturns(result1 []*githubapp.Repository, result2 error) {
+	fake.repositoriesMutex.Lock()
+	defer fake.repositoriesMutex.Unlock()
+	fake.RepositoriesStub = nil
+	fake.repositoriesReturns = struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) RepositoriesReturnsOnCall(i int, result1 []*githubapp.Repository, result2 error) {
+	fake.repositoriesMutex.Lock()
+	defer fake.repositoriesMutex.Unlock()
+	fake.RepositoriesStub = nil
+	if fake.repositoriesReturnsOnCall == nil {
+		fake.repositoriesReturnsOnCall = make(map[int]struct {
+			result1 []*githubapp.Repository
+			result2 error
+		})
+	}
+	fake.repositoriesReturnsOnCall[i] = struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) RepositoriesContext(arg1 context.Context, arg2 string) ([]*githubapp.Repository, error) {
+	fake.repositoriesContextMutex.Lock()
+	ret, specificReturn := fake.repositoriesContextReturnsOnCall[len(fake.repositoriesContextArgsForCall)]
+	fake.repositoriesContextArgsForCall = append(fake.repositoriesContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RepositoriesContextStub
+	fakeReturns := fake.repositoriesContextReturns
+	fake.recordInvocation("RepositoriesContext", []interface{}{arg1, arg2})
+	fake.repositoriesContextMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthenticator) RepositoriesContextCallCount() int {
+	fake.repositoriesContextMutex.RLock()
+	defer fake.repositoriesContextMutex.RUnlock()
+	return len(fake.repositoriesContextArgsForCall)
+}
+
+func (fake *FakeAuthenticator) RepositoriesContextCalls(stub func(context.Context, string) ([]*githubapp.Repository, error)) {
+	fake.repositoriesContextMutex.Lock()
+	defer fake.repositoriesContextMutex.Unlock()
+	fake.RepositoriesContextStub = stub
+}
+
+func (fake *FakeAuthenticator) RepositoriesContextArgsForCall(i int) (context.Context, string) {
+	fake.repositoriesContextMutex.RLock()
+	defer fake.repositoriesContextMutex.RUnlock()
+	argsForCall := fake.repositoriesContextArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAuthenticator) RepositoriesContextReturns(result1 []*githubapp.Repository, result2 error) {
+	fake.repositoriesContextMutex.Lock()
+	defer fake.repositoriesContextMutex.Unlock()
+	fake.RepositoriesContextStub = nil
+	fake.repositoriesContextReturns = struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) RepositoriesContextReturnsOnCall(i int, result1 []*githubapp.Repository, result2 error) {
+	fake.repositoriesContextMutex.Lock()
+	defer fake.repositoriesContextMutex.Unlock()
+	fake.RepositoriesContextStub = nil
+	if fake.repositoriesContextReturnsOnCall == nil {
+		fake.repositoriesContextReturnsOnCall = make(map[int]struct {
+			result1 []*githubapp.Repository
+			result2 error
+		})
+	}
+	fake.repositoriesContextReturnsOnCall[i] = struct {
+		result1 []*githubapp.Repository
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthenticator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAuthenticator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ githubapp.Authenticator = new(FakeAuthenticator)
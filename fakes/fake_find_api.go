@@ -0,0 +1,377 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/telia-oss/githubapp"
+)
+
+type FakeAppsFindAPI struct {
+	CreateInstallationTokenStub        func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error)
+	createInstallationTokenMutex       sync.RWMutex
+	createInstallationTokenArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 *github.InstallationTokenOptions
+	}
+	createInstallationTokenReturns struct {
+		result1 *github.InstallationToken
+		result2 *github.Response
+		result3 error
+	}
+	createInstallationTokenReturnsOnCall map[int]struct {
+		result1 *github.InstallationToken
+		result2 *github.Response
+		result3 error
+	}
+	FindOrganizationInstallationStub        func(context.Context, string) (*github.Installation, *github.Response, error)
+	findOrganizationInstallationMutex       sync.RWMutex
+	findOrganizationInstallationArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findOrganizationInstallationReturns struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	findOrganizationInstallationReturnsOnCall map[int]struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	FindUserInstallationStub        func(context.Context, string) (*github.Installation, *github.Response, error)
+	findUserInstallationMutex       sync.RWMutex
+	findUserInstallationArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findUserInstallationReturns struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	findUserInstallationReturnsOnCall map[int]struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	ListInstallationsStub        func(context.Context, *github.ListOptions) ([]*github.Installation, *github.Response, error)
+	listInstallationsMutex       sync.RWMutex
+	listInstallationsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *github.ListOptions
+	}
+	listInstallationsReturns struct {
+		result1 []*github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	listInstallationsReturnsOnCall map[int]struct {
+		result1 []*github.Installation
+		result2 *github.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationToken(arg1 context.Context, arg2 int64, arg3 *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+	fake.createInstallationTokenMutex.Lock()
+	ret, specificReturn := fake.createInstallationTokenReturnsOnCall[len(fake.createInstallationTokenArgsForCall)]
+	fake.createInstallationTokenArgsForCall = append(fake.createInstallationTokenArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 *github.InstallationTokenOptions
+	}{arg1, arg2, arg3})
+	stub := fake.CreateInstallationTokenStub
+	fakeReturns := fake.createInstallationTokenReturns
+	fake.recordInvocation("CreateInstallationToken", []interface{}{arg1, arg2, arg3})
+	fake.createInstallationTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationTokenCallCount() int {
+	fake.createInstallationTokenMutex.RLock()
+	defer fake.createInstallationTokenMutex.RUnlock()
+	return len(fake.createInstallationTokenArgsForCall)
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationTokenCalls(stub func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error)) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = stub
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationTokenArgsForCall(i int) (context.Context, int64, *github.InstallationTokenOptions) {
+	fake.createInstallationTokenMutex.RLock()
+	defer fake.createInstallationTokenMutex.RUnlock()
+	argsForCall := fake.createInstallationTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationTokenReturns(result1 *github.InstallationToken, result2 *github.Response, result3 error) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = nil
+	fake.createInstallationTokenReturns = struct {
+		result1 *github.InstallationToken
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) CreateInstallationTokenReturnsOnCall(i int, result1 *github.InstallationToken, result2 *github.Response, result3 error) {
+	fake.createInstallationTokenMutex.Lock()
+	defer fake.createInstallationTokenMutex.Unlock()
+	fake.CreateInstallationTokenStub = nil
+	if fake.createInstallationTokenReturnsOnCall == nil {
+		fake.createInstallationTokenReturnsOnCall = make(map[int]struct {
+			result1 *github.InstallationToken
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createInstallationTokenReturnsOnCall[i] = struct {
+		result1 *github.InstallationToken
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallation(arg1 context.Context, arg2 string) (*github.Installation, *github.Response, error) {
+	fake.findOrganizationInstallationMutex.Lock()
+	ret, specificReturn := fake.findOrganizationInstallationReturnsOnCall[len(fake.findOrganizationInstallationArgsForCall)]
+	fake.findOrganizationInstallationArgsForCall = append(fake.findOrganizationInstallationArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindOrganizationInstallationStub
+	fakeReturns := fake.findOrganizationInstallationReturns
+	fake.recordInvocation("FindOrganizationInstallation", []interface{}{arg1, arg2})
+	fake.findOrganizationInstallationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallationCallCount() int {
+	fake.findOrganizationInstallationMutex.RLock()
+	defer fake.findOrganizationInstallationMutex.RUnlock()
+	return len(fake.findOrganizationInstallationArgsForCall)
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallationCalls(stub func(context.Context, string) (*github.Installation, *github.Response, error)) {
+	fake.findOrganizationInstallationMutex.Lock()
+	defer fake.findOrganizationInstallationMutex.Unlock()
+	fake.FindOrganizationInstallationStub = stub
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallationArgsForCall(i int) (context.Context, string) {
+	fake.findOrganizationInstallationMutex.RLock()
+	defer fake.findOrganizationInstallationMutex.RUnlock()
+	argsForCall := fake.findOrganizationInstallationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallationReturns(result1 *github.Installation, result2 *github.Response, result3 error) {
+	fake.findOrganizationInstallationMutex.Lock()
+	defer fake.findOrganizationInstallationMutex.Unlock()
+	fake.FindOrganizationInstallationStub = nil
+	fake.findOrganizationInstallationReturns = struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) FindOrganizationInstallationReturnsOnCall(i int, result1 *github.Installation, result2 *github.Response, result3 error) {
+	fake.findOrganizationInstallationMutex.Lock()
+	defer fake.findOrganizationInstallationMutex.Unlock()
+	fake.FindOrganizationInstallationStub = nil
+	if fake.findOrganizationInstallationReturnsOnCall == nil {
+		fake.findOrganizationInstallationReturnsOnCall = make(map[int]struct {
+			result1 *github.Installation
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.findOrganizationInstallationReturnsOnCall[i] = struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallation(arg1 context.Context, arg2 string) (*github.Installation, *github.Response, error) {
+	fake.findUserInstallationMutex.Lock()
+	ret, specificReturn := fake.findUserInstallationReturnsOnCall[len(fake.findUserInstallationArgsForCall)]
+	fake.findUserInstallationArgsForCall = append(fake.findUserInstallationArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.FindUserInstallationStub
+	fakeReturns := fake.findUserInstallationReturns
+	fake.recordInvocation("FindUserInstallation", []interface{}{arg1, arg2})
+	fake.findUserInstallationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallationCallCount() int {
+	fake.findUserInstallationMutex.RLock()
+	defer fake.findUserInstallationMutex.RUnlock()
+	return len(fake.findUserInstallationArgsForCall)
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallationCalls(stub func(context.Context, string) (*github.Installation, *github.Response, error)) {
+	fake.findUserInstallationMutex.Lock()
+	defer fake.findUserInstallationMutex.Unlock()
+	fake.FindUserInstallationStub = stub
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallationArgsForCall(i int) (context.Context, string) {
+	fake.findUserInstallationMutex.RLock()
+	defer fake.findUserInstallationMutex.RUnlock()
+	argsForCall := fake.findUserInstallationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallationReturns(result1 *github.Installation, result2 *github.Response, result3 error) {
+	fake.findUserInstallationMutex.Lock()
+	defer fake.findUserInstallationMutex.Unlock()
+	fake.FindUserInstallationStub = nil
+	fake.findUserInstallationReturns = struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) FindUserInstallationReturnsOnCall(i int, result1 *github.Installation, result2 *github.Response, result3 error) {
+	fake.findUserInstallationMutex.Lock()
+	defer fake.findUserInstallationMutex.Unlock()
+	fake.FindUserInstallationStub = nil
+	if fake.findUserInstallationReturnsOnCall == nil {
+		fake.findUserInstallationReturnsOnCall = make(map[int]struct {
+			result1 *github.Installation
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.findUserInstallationReturnsOnCall[i] = struct {
+		result1 *github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) ListInstallations(arg1 context.Context, arg2 *github.ListOptions) ([]*github.Installation, *github.Response, error) {
+	fake.listInstallationsMutex.Lock()
+	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
+	fake.listInstallationsArgsForCall = append(fake.listInstallationsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *github.ListOptions
+	}{arg1, arg2})
+	stub := fake.ListInstallationsStub
+	fakeReturns := fake.listInstallationsReturns
+	fake.recordInvocation("ListInstallations", []interface{}{arg1, arg2})
+	fake.listInstallationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAppsFindAPI) ListInstallationsCallCount() int {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	return len(fake.listInstallationsArgsForCall)
+}
+
+func (fake *FakeAppsFindAPI) ListInstallationsCalls(stub func(context.Context, *github.ListOptions) ([]*github.Installation, *github.Response, error)) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = stub
+}
+
+func (fake *FakeAppsFindAPI) ListInstallationsArgsForCall(i int) (context.Context, *github.ListOptions) {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	argsForCall := fake.listInstallationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppsFindAPI) ListInstallationsReturns(result1 []*github.Installation, result2 *github.Response, result3 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	fake.listInstallationsReturns = struct {
+		result1 []*github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) ListInstallationsReturnsOnCall(i int, result1 []*github.Installation, result2 *github.Response, result3 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	if fake.listInstallationsReturnsOnCall == nil {
+		fake.listInstallationsReturnsOnCall = make(map[int]struct {
+			result1 []*github.Installation
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.listInstallationsReturnsOnCall[i] = struct {
+		result1 []*github.Installation
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsFindAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAppsFindAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ githubapp.AppsFindAPI = new(FakeAppsFindAPI)
@@ -187,10 +187,6 @@ func (fake *FakeAppsJWTAPI) ListInstallationsReturnsOnCall(i int, result1 []*git
 func (fake *FakeAppsJWTAPI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.createInstallationTokenMutex.RLock()
-	defer fake.createInstallationTokenMutex.RUnlock()
-	fake.listInstallationsMutex.RLock()
-	defer fake.listInstallationsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,200 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/telia-oss/githubapp"
+)
+
+type FakeAppsRevokeAPI struct {
+	ListReposStub        func(context.Context, *github.ListOptions) (*github.ListRepositories, *github.Response, error)
+	listReposMutex       sync.RWMutex
+	listReposArgsForCall []struct {
+		arg1 context.Context
+		arg2 *github.ListOptions
+	}
+	listReposReturns struct {
+		result1 *github.ListRepositories
+		result2 *github.Response
+		result3 error
+	}
+	listReposReturnsOnCall map[int]struct {
+		result1 *github.ListRepositories
+		result2 *github.Response
+		result3 error
+	}
+	RevokeInstallationTokenStub        func(context.Context) (*github.Response, error)
+	revokeInstallationTokenMutex       sync.RWMutex
+	revokeInstallationTokenArgsForCall []struct {
+		arg1 context.Context
+	}
+	revokeInstallationTokenReturns struct {
+		result1 *github.Response
+		result2 error
+	}
+	revokeInstallationTokenReturnsOnCall map[int]struct {
+		result1 *github.Response
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAppsRevokeAPI) ListRepos(arg1 context.Context, arg2 *github.ListOptions) (*github.ListRepositories, *github.Response, error) {
+	fake.listReposMutex.Lock()
+	ret, specificReturn := fake.listReposReturnsOnCall[len(fake.listReposArgsForCall)]
+	fake.listReposArgsForCall = append(fake.listReposArgsForCall, struct {
+		arg1 context.Context
+		arg2 *github.ListOptions
+	}{arg1, arg2})
+	stub := fake.ListReposStub
+	fakeReturns := fake.listReposReturns
+	fake.recordInvocation("ListRepos", []interface{}{arg1, arg2})
+	fake.listReposMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAppsRevokeAPI) ListReposCallCount() int {
+	fake.listReposMutex.RLock()
+	defer fake.listReposMutex.RUnlock()
+	return len(fake.listReposArgsForCall)
+}
+
+func (fake *FakeAppsRevokeAPI) ListReposCalls(stub func(context.Context, *github.ListOptions) (*github.ListRepositories, *github.Response, error)) {
+	fake.listReposMutex.Lock()
+	defer fake.listReposMutex.Unlock()
+	fake.ListReposStub = stub
+}
+
+func (fake *FakeAppsRevokeAPI) ListReposArgsForCall(i int) (context.Context, *github.ListOptions) {
+	fake.listReposMutex.RLock()
+	defer fake.listReposMutex.RUnlock()
+	argsForCall := fake.listReposArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAppsRevokeAPI) ListReposReturns(result1 *github.ListRepositories, result2 *github.Response, result3 error) {
+	fake.listReposMutex.Lock()
+	defer fake.listReposMutex.Unlock()
+	fake.ListReposStub = nil
+	fake.listReposReturns = struct {
+		result1 *github.ListRepositories
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsRevokeAPI) ListReposReturnsOnCall(i int, result1 *github.ListRepositories, result2 *github.Response, result3 error) {
+	fake.listReposMutex.Lock()
+	defer fake.listReposMutex.Unlock()
+	fake.ListReposStub = nil
+	if fake.listReposReturnsOnCall == nil {
+		fake.listReposReturnsOnCall = make(map[int]struct {
+			result1 *github.ListRepositories
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.listReposReturnsOnCall[i] = struct {
+		result1 *github.ListRepositories
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationToken(arg1 context.Context) (*github.Response, error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	ret, specificReturn := fake.revokeInstallationTokenReturnsOnCall[len(fake.revokeInstallationTokenArgsForCall)]
+	fake.revokeInstallationTokenArgsForCall = append(fake.revokeInstallationTokenArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RevokeInstallationTokenStub
+	fakeReturns := fake.revokeInstallationTokenReturns
+	fake.recordInvocation("RevokeInstallationToken", []interface{}{arg1})
+	fake.revokeInstallationTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationTokenCallCount() int {
+	fake.revokeInstallationTokenMutex.RLock()
+	defer fake.revokeInstallationTokenMutex.RUnlock()
+	return len(fake.revokeInstallationTokenArgsForCall)
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationTokenCalls(stub func(context.Context) (*github.Response, error)) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = stub
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationTokenArgsForCall(i int) context.Context {
+	fake.revokeInstallationTokenMutex.RLock()
+	defer fake.revokeInstallationTokenMutex.RUnlock()
+	argsForCall := fake.revokeInstallationTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationTokenReturns(result1 *github.Response, result2 error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = nil
+	fake.revokeInstallationTokenReturns = struct {
+		result1 *github.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppsRevokeAPI) RevokeInstallationTokenReturnsOnCall(i int, result1 *github.Response, result2 error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = nil
+	if fake.revokeInstallationTokenReturnsOnCall == nil {
+		fake.revokeInstallationTokenReturnsOnCall = make(map[int]struct {
+			result1 *github.Response
+			result2 error
+		})
+	}
+	fake.revokeInstallationTokenReturnsOnCall[i] = struct {
+		result1 *github.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppsRevokeAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAppsRevokeAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ githubapp.AppsRevokeAPI = new(FakeAppsRevokeAPI)
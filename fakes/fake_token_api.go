@@ -101,8 +101,6 @@ func (fake *FakeAppsTokenAPI) ListReposReturnsOnCall(i int, result1 *github.List
 func (fake *FakeAppsTokenAPI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.listReposMutex.RLock()
-	defer fake.listReposMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,101 @@
+package githubapp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Faults describes the degradation injected by NewFaultTransport. Rates are between 0 (never) and 1 (always).
+type Faults struct {
+	// Latency is added to every request before it is sent.
+	Latency time.Duration
+
+	// ErrorRate is the fraction of requests that fail with 502 Bad Gateway.
+	ErrorRate float64
+
+	// RateLimitRate is the fraction of requests that fail with 403 Forbidden and exhausted rate limit headers,
+	// which go-github returns as a *github.RateLimitError.
+	RateLimitRate float64
+}
+
+// WithFaults injects faults into requests made by the default installation clients and transports returned by the App. It can
+// be used in tests and staging environments to check that consumers of the App handle a degraded Github API.
+func WithFaults(faults Faults) option {
+	return func(a *App) {
+		a.faults = &faults
+	}
+}
+
+// NewFaultTransport returns a http.RoundTripper that injects the faults into requests. Failed requests are not sent to base.
+// If base is nil, http.DefaultTransport is used.
+func NewFaultTransport(base http.RoundTripper, faults Faults) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &faultTransport{base: base, faults: faults, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+type faultTransport struct {
+	base   http.RoundTripper
+	faults Faults
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.faults.Latency > 0 {
+		timer := time.NewTimer(t.faults.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	switch {
+	case t.roll(t.faults.ErrorRate):
+		return faultResponse(req, http.StatusBadGateway, nil, `{"message":"Server Error"}`), nil
+	case t.roll(t.faults.RateLimitRate):
+		header := http.Header{}
+		header.Set("X-RateLimit-Limit", "5000")
+		header.Set("X-RateLimit-Remaining", "0")
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		return faultResponse(req, http.StatusForbidden, header, `{"message":"API rate limit exceeded"}`), nil
+	}
+	return t.base.RoundTrip(req)
+}
+
+// roll returns true with the given probability.
+func (t *faultTransport) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rand.Float64() < rate
+}
+
+func faultResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,55 @@
+package githubapp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestFaultTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request to the server")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		faults      githubapp.Faults
+		check       func(error) bool
+	}{
+		{
+			description: "returns server errors",
+			faults:      githubapp.Faults{ErrorRate: 1},
+			check: func(err error) bool {
+				var e *github.ErrorResponse
+				return errors.As(err, &e) && e.Response.StatusCode == http.StatusBadGateway
+			},
+		},
+		{
+			description: "returns rate limit errors",
+			faults:      githubapp.Faults{RateLimitRate: 1},
+			check: func(err error) bool {
+				var e *github.RateLimitError
+				return errors.As(err, &e)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			client, err := github.NewEnterpriseClient(server.URL, server.URL, &http.Client{
+				Transport: githubapp.NewFaultTransport(nil, tc.faults),
+			})
+			noError(t, err)
+
+			_, _, err = client.Repositories.Get(context.TODO(), "owner", "repository")
+			isEqual(t, true, tc.check(err))
+		})
+	}
+}
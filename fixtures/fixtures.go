@@ -0,0 +1,162 @@
+// Package fixtures generates deterministic Github API fixtures for installations of any size, and serves them with the same
+// pagination (including Link headers) as the Github API, so that the caching and pagination in the App can be tested at scale.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// defaultPerPage is the page size used by the Github API when per_page is not set.
+const defaultPerPage = 30
+
+// Installations returns n installations, with IDs from 1 to n and owners named owner-1 to owner-n.
+func Installations(n int) []*github.Installation {
+	installations := make([]*github.Installation, n)
+	for i := range installations {
+		installations[i] = &github.Installation{
+			ID:                  github.Int64(int64(i + 1)),
+			Account:             &github.User{Login: github.String(Owner(i + 1))},
+			RepositorySelection: github.String("selected"),
+		}
+	}
+	return installations
+}
+
+// Repositories returns n repositories, with IDs from 1 to n and named repository-1 to repository-n.
+func Repositories(n int) []*github.Repository {
+	repositories := make([]*github.Repository, n)
+	for i := range repositories {
+		repositories[i] = &github.Repository{
+			ID:   github.Int64(int64(i + 1)),
+			Name: github.String(Repository(i + 1)),
+		}
+	}
+	return repositories
+}
+
+// Owner returns the name of the owner of the installation with the given ID.
+func Owner(id int) string {
+	return fmt.Sprintf("owner-%d", id)
+}
+
+// Repository returns the name of the repository with the given ID.
+func Repository(id int) string {
+	return fmt.Sprintf("repository-%d", id)
+}
+
+// Server serves the installations and repositories generated by Installations and Repositories. Each installation has the
+// same repositories.
+type Server struct {
+	*httptest.Server
+	installations []*github.Installation
+	repositories  []*github.Repository
+}
+
+// NewServer starts a Server with the given number of installations and repositories per installation. The caller should
+// call Close when done.
+func NewServer(installations, repositories int) *Server {
+	s := &Server{installations: Installations(installations), repositories: Repositories(repositories)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app/installations", s.listInstallations)
+	mux.HandleFunc("/app/installations/", s.createInstallationToken)
+	mux.HandleFunc("/installation/repositories", s.listRepositories)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AppsClient returns an AppsJWTAPI client for the Server.
+func (s *Server) AppsClient() githubapp.AppsJWTAPI {
+	client := github.NewClient(s.Client())
+	client.BaseURL, _ = url.Parse(s.URL + "/")
+	return client.Apps
+}
+
+// Endpoint returns the URL of the Server, which can be passed to githubapp.WithEndpoint to send requests for api.github.com
+// made by the installation clients of the App to the Server.
+func (s *Server) Endpoint() *url.URL {
+	u, _ := url.Parse(s.URL)
+	return u
+}
+
+func (s *Server) listInstallations(w http.ResponseWriter, r *http.Request) {
+	start, end := paginate(w, r, len(s.installations))
+	writeJSON(w, http.StatusOK, s.installations[start:end])
+}
+
+func (s *Server) createInstallationToken(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/app/installations/"), "/access_tokens")
+	if r.Method != http.MethodPost || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	writeJSON(w, http.StatusCreated, &github.InstallationToken{Token: github.String("token-" + id), ExpiresAt: &expiresAt})
+}
+
+func (s *Server) listRepositories(w http.ResponseWriter, r *http.Request) {
+	start, end := paginate(w, r, len(s.repositories))
+	writeJSON(w, http.StatusOK, &github.ListRepositories{TotalCount: github.Int(len(s.repositories)), Repositories: s.repositories[start:end]})
+}
+
+// paginate sets the Link header for the requested page of a list with total items, and returns the range of the page.
+func paginate(w http.ResponseWriter, r *http.Request, total int) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = defaultPerPage
+	}
+	last := (total + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+
+	var links []string
+	link := func(page int, rel string) {
+		u := *r.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<http://%s%s>; rel="%s"`, r.Host, u.RequestURI(), rel))
+	}
+	if page > 1 {
+		link(1, "first")
+		link(page-1, "prev")
+	}
+	if page < last {
+		link(page+1, "next")
+		link(last, "last")
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	start, end := (page-1)*perPage, page*perPage
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
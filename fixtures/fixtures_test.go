@@ -0,0 +1,57 @@
+package fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fixtures"
+)
+
+func TestServer(t *testing.T) {
+	server := fixtures.NewServer(150, 1050)
+	defer server.Close()
+
+	app := githubapp.New(server.AppsClient(), githubapp.WithEndpoint("api.github.com", server.Endpoint()))
+
+	installations, err := app.Installations()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(installations) != 150 {
+		t.Errorf("expected 150 installations, got %d", len(installations))
+	}
+
+	repositories, err := app.Repositories(fixtures.Owner(150))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(repositories) != 1050 {
+		t.Errorf("expected 1050 repositories, got %d", len(repositories))
+	}
+
+	token, err := app.CreateInstallationToken(fixtures.Owner(150), []string{fixtures.Repository(1050)}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if token.GetToken() != "token-150" {
+		t.Errorf("expected token-150, got %s", token.GetToken())
+	}
+}
+
+func TestServerContentType(t *testing.T) {
+	server := fixtures.NewServer(1, 1)
+	defer server.Close()
+
+	res, err := http.Post(server.URL+"/app/installations/1/access_tokens", "application/json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, res.StatusCode)
+	}
+	if contentType := res.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected content type application/json, got '%s'", contentType)
+	}
+}
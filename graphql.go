@@ -0,0 +1,37 @@
+package githubapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// GraphQLPageFunc fetches a single page of a paginated GraphQL query. It is called with the cursor of the page to fetch
+// (nil for the first page) and returns the cursor of the next page, or nil if there are no more pages.
+type GraphQLPageFunc func(ctx context.Context, client *githubv4.Client, cursor *githubv4.String) (*githubv4.String, error)
+
+// PaginateGraphQL runs each of the paginated queries as the installation for the owner, until all pages have been fetched. The
+// installation token is renewed between pages when it is about to expire, so long walks are not limited by the token lifetime.
+func (a *App) PaginateGraphQL(ctx context.Context, owner string, queries ...GraphQLPageFunc) error {
+	client := githubv4.NewClient(&http.Client{
+		Transport: a.InstallationTransport(owner),
+	})
+	for _, query := range queries {
+		var cursor *githubv4.String
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			next, err := query(ctx, client, cursor)
+			if err != nil {
+				return err
+			}
+			if next == nil {
+				break
+			}
+			cursor = next
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package githubapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestPaginateGraphQL(t *testing.T) {
+	var (
+		client    = &fakes.FakeAppsJWTAPI{}
+		expiresAt = time.Now().Add(1 * time.Hour)
+	)
+
+	var (
+		authorization []string
+		cursors       []interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isEqual(t, "/graphql", r.URL.Path)
+		authorization = append(authorization, r.Header.Get("Authorization"))
+
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		noError(t, json.NewDecoder(r.Body).Decode(&body))
+		cursors = append(cursors, body.Variables["cursor"])
+
+		page := len(cursors)
+		fmt.Fprintf(w, `{"data":{"viewer":{"repositories":{"nodes":[{"name":"repository-%d"}],"pageInfo":{"endCursor":"cursor-%d","hasNextPage":%t}}}}}`, page, page, page < 3)
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	noError(t, err)
+	gh := githubapp.New(client,
+		githubapp.WithEndpoint("api.github.com", target),
+		githubapp.WithInstallationClientFactory(func(string) githubapp.AppsTokenAPI { return &fakes.FakeAppsRevokeAPI{} }),
+		githubapp.WithTokenLifetime(50*time.Millisecond),
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		token := fmt.Sprintf("token-%d", client.CreateInstallationTokenCallCount())
+		return &github.InstallationToken{Token: github.String(token), ExpiresAt: &expiresAt}, nil, nil
+	})
+
+	var names []string
+	err = gh.PaginateGraphQL(context.TODO(), "owner", func(ctx context.Context, client *githubv4.Client, cursor *githubv4.String) (*githubv4.String, error) {
+		var query struct {
+			Viewer struct {
+				Repositories struct {
+					Nodes    []struct{ Name string }
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"repositories(first: 1, after: $cursor)"`
+			}
+		}
+		// Let the token expire before the last page.
+		if len(names) == 2 {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if err := client.Query(ctx, &query, map[string]interface{}{"cursor": cursor}); err != nil {
+			return nil, err
+		}
+		for _, node := range query.Viewer.Repositories.Nodes {
+			names = append(names, node.Name)
+		}
+		if !query.Viewer.Repositories.PageInfo.HasNextPage {
+			return nil, nil
+		}
+		return &query.Viewer.Repositories.PageInfo.EndCursor, nil
+	})
+	noError(t, err)
+
+	isEqual(t, []string{"repository-1", "repository-2", "repository-3"}, names)
+	isEqual(t, []interface{}{nil, "cursor-1", "cursor-2"}, cursors)
+	isEqual(t, []string{"token token-1", "token token-1", "token token-2"}, authorization)
+}
@@ -0,0 +1,27 @@
+// Package atomicfile writes files so that readers never see a partially written file.
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Write atomically replaces the file with the content, and makes it only readable by the current user. The content is written
+// to a temporary file in the same directory, which is then renamed to the path.
+func Write(path, content string) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,151 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operations that latencies and responses are reported for.
+const (
+	// OperationMint is creating an installation token for a caller.
+	OperationMint = "mint"
+
+	// OperationListInstallations is listing the installations to refresh the cache.
+	OperationListInstallations = "list_installations"
+
+	// OperationListRepositories is listing the repositories of an installation to refresh the cache.
+	OperationListRepositories = "list_repositories"
+
+	// OperationRevoke is revoking an installation token.
+	OperationRevoke = "revoke"
+)
+
+// latencyWindow is the number of recent samples that are kept for each operation.
+const latencyWindow = 100
+
+// Latency summarises the recent latencies of an operation.
+type Latency struct {
+	Operation string
+
+	// Samples is the number of recent calls the percentiles are computed from, up to the 100 most recent.
+	Samples int
+
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// LatencySink is called with the latency of each operation, and can be used to export latencies as metrics. It may be called
+// while the App is locked, and must not call methods on the App.
+type LatencySink func(operation string, latency time.Duration)
+
+// WithLatencySink registers a LatencySink that is called in addition to the in-memory tracking.
+func WithLatencySink(sink LatencySink) option {
+	return func(a *App) {
+		a.latencies.sink = sink
+	}
+}
+
+// Latency returns the latency percentiles of the recent calls for each operation that has been performed, sorted by operation.
+func (a *App) Latency() []Latency {
+	a.latencies.mu.Lock()
+	operations := make([]string, 0, len(a.latencies.samples))
+	for operation := range a.latencies.samples {
+		operations = append(operations, operation)
+	}
+	a.latencies.mu.Unlock()
+	sort.Strings(operations)
+
+	latency := make([]Latency, 0, len(operations))
+	for _, operation := range operations {
+		l := Latency{Operation: operation}
+		l.P50, _ = a.latencies.percentile(operation, 50)
+		l.P90, _ = a.latencies.percentile(operation, 90)
+		l.P99, l.Samples = a.latencies.percentileN(operation, 99)
+		latency = append(latency, l)
+	}
+	return latency
+}
+
+// latencies keeps the most recent latencies of each operation. The zero value is ready to use.
+type latencies struct {
+	sink LatencySink
+
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+	next    map[string]int
+}
+
+// since records the time since start as a sample for the operation.
+func (l *latencies) since(operation string, start time.Time) {
+	l.record(operation, time.Since(start))
+}
+
+// record adds a sample for the operation, replacing the oldest sample once the window is full.
+func (l *latencies) record(operation string, d time.Duration) {
+	if l.sink != nil {
+		l.sink(operation, d)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.samples == nil {
+		l.samples, l.next = make(map[string][]time.Duration), make(map[string]int)
+	}
+	if samples := l.samples[operation]; len(samples) < latencyWindow {
+		l.samples[operation] = append(samples, d)
+		return
+	}
+	l.samples[operation][l.next[operation]] = d
+	l.next[operation] = (l.next[operation] + 1) % latencyWindow
+}
+
+// percentile returns the p-th percentile (0-100) of the recent samples for the operation, or false if there are none.
+func (l *latencies) percentile(operation string, p float64) (time.Duration, bool) {
+	d, n := l.percentileN(operation, p)
+	return d, n > 0
+}
+
+// percentileN returns the p-th percentile (0-100) of the recent samples for the operation, and the number of samples.
+func (l *latencies) percentileN(operation string, p float64) (time.Duration, int) {
+	l.mu.Lock()
+	samples := append([]time.Duration(nil), l.samples[operation]...)
+	l.mu.Unlock()
+
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	i := int(p / 100 * float64(len(samples)-1))
+	return samples[i], len(samples)
+}
+
+// checkDeadline returns ErrWouldExceedDeadline if the context has a deadline that is shorter than the median latency of
+// the operation.
+func (a *App) checkDeadline(ctx context.Context, operation string) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	estimate, ok := a.latencies.percentile(operation, 50)
+	if remaining := time.Until(deadline); ok && remaining < estimate {
+		return &ErrWouldExceedDeadline{Operation: operation, Estimate: estimate, Remaining: remaining}
+	}
+	return nil
+}
+
+// ErrWouldExceedDeadline is returned if the cache must be filled before a request can be served, and the time left until the
+// deadline of the context is shorter than filling the cache has recently taken. Requests with short deadlines can avoid this
+// by warming the cache in advance with Refresh, or by using the CacheRefreshAhead strategy.
+type ErrWouldExceedDeadline struct {
+	Operation string
+	Estimate  time.Duration
+	Remaining time.Duration
+}
+
+func (e *ErrWouldExceedDeadline) Error() string {
+	return fmt.Sprintf("%s would exceed deadline: expected to take %s, %s remaining (warm the cache with Refresh)", e.Operation, e.Estimate, e.Remaining)
+}
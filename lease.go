@@ -0,0 +1,148 @@
+package githubapp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Scope describes what an installation token grants access to.
+type Scope struct {
+	Owner        string
+	Repositories []string
+	Permissions  *Permissions
+}
+
+// Lease is an installation token issued for a Scope, which can be renewed and revoked.
+type Lease struct {
+	ID    string
+	Scope Scope
+
+	app     *App
+	mu      sync.Mutex
+	token   *Token
+	revoked bool
+}
+
+// Lease creates a new installation token for the scope and returns it as a Lease. The lease is kept by the App until it is
+// revoked, so that it can be renewed after its token has expired.
+func (a *App) Lease(scope Scope) (*Lease, error) {
+	return a.LeaseContext(context.Background(), scope)
+}
+
+// LeaseContext is like Lease, but uses the context for all requests made to create the token.
+func (a *App) LeaseContext(ctx context.Context, scope Scope) (*Lease, error) {
+	id, err := leaseID()
+	if err != nil {
+		return nil, err
+	}
+	token, err := a.createInstallationToken(ctx, scope.Owner, scope.Repositories, scope.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	l := &Lease{ID: id, Scope: scope, app: a, token: token}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.leases == nil {
+		a.leases = make(map[string]*Lease)
+	}
+	a.leases[l.ID] = l
+	return l, nil
+}
+
+// GetLease returns the lease with the given ID, if it exists and has not been revoked. The token of the lease might have
+// expired, in which case the lease can be renewed.
+func (a *App) GetLease(id string) (*Lease, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	l, ok := a.leases[id]
+	return l, ok
+}
+
+// Token returns the current installation token for the lease. The token is no longer valid once the lease has been revoked.
+func (l *Lease) Token() *Token {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token
+}
+
+// ExpiresAt returns the time at which the current token for the lease expires.
+func (l *Lease) ExpiresAt() time.Time {
+	return l.Token().GetExpiresAt()
+}
+
+// Expired returns true if the current token for the lease has expired, or the lease has been revoked.
+func (l *Lease) Expired() bool {
+	return l.Revoked() || !l.ExpiresAt().After(time.Now())
+}
+
+// Revoked returns true if the lease has been revoked.
+func (l *Lease) Revoked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.revoked
+}
+
+// Renew replaces the token for the lease with a new one for the same scope. The previous token is
+// not revoked, and remains valid until it expires. ErrLeaseRevoked is returned if the lease has been revoked.
+func (l *Lease) Renew() error {
+	return l.RenewContext(context.Background())
+}
+
+// RenewContext is like Renew, but uses the context for all requests made to create the token.
+func (l *Lease) RenewContext(ctx context.Context) error {
+	if l.Revoked() {
+		return ErrLeaseRevoked(l.ID)
+	}
+	token, err := l.app.createInstallationToken(ctx, l.Scope.Owner, l.Scope.Repositories, l.Scope.Permissions)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.revoked {
+		return ErrLeaseRevoked(l.ID)
+	}
+	l.token = token
+	return nil
+}
+
+// Revoke revokes the token for the lease and removes it from the App. After that the lease is expired, and can no longer be renewed.
+func (l *Lease) Revoke() error {
+	return l.RevokeContext(context.Background())
+}
+
+// RevokeContext is like Revoke, but uses the context for the request made to revoke the token.
+func (l *Lease) RevokeContext(ctx context.Context) error {
+	if err := l.app.revokeToken(ctx, l.Scope.Owner, l.Token().GetToken()); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.revoked = true
+	l.mu.Unlock()
+
+	l.app.mu.Lock()
+	defer l.app.mu.Unlock()
+	delete(l.app.leases, l.ID)
+	return nil
+}
+
+// ErrLeaseRevoked is returned if a lease is renewed after it has been revoked.
+type ErrLeaseRevoked string
+
+func (e ErrLeaseRevoked) Error() string {
+	return fmt.Sprintf("lease has been revoked: '%s'", string(e))
+}
+
+func leaseID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lease ID: %s", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,197 @@
+package githubapp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestLease(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsRevokeAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		expiresAt     = time.Now().Add(1 * time.Hour)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	expired := time.Now().Add(-1 * time.Minute)
+	client.CreateInstallationTokenReturnsOnCall(0, &github.InstallationToken{Token: github.String("first"), ExpiresAt: &expired}, nil, nil)
+	client.CreateInstallationTokenReturnsOnCall(1, &github.InstallationToken{Token: github.String("second"), ExpiresAt: &expiresAt}, nil, nil)
+
+	lease, err := gh.Lease(githubapp.Scope{Owner: "owner"})
+	noError(t, err)
+	isEqual(t, "first", lease.Token().GetToken())
+	isEqual(t, true, lease.Expired())
+
+	// Leases are kept after their token has expired, so that they can be renewed.
+	_, err = gh.Lease(githubapp.Scope{Owner: "owner"})
+	noError(t, err)
+	got, ok := gh.GetLease(lease.ID)
+	isEqual(t, true, ok)
+	isEqual(t, lease, got)
+
+	client.CreateInstallationTokenReturnsOnCall(2, &github.InstallationToken{Token: github.String("third"), ExpiresAt: &expiresAt}, nil, nil)
+	noError(t, lease.Renew())
+	isEqual(t, "third", lease.Token().GetToken())
+	isEqual(t, false, lease.Expired())
+
+	noError(t, lease.Revoke())
+	isEqual(t, 1, tokenClient.RevokeInstallationTokenCallCount())
+	isEqual(t, true, lease.Revoked())
+	isEqual(t, true, lease.Expired())
+	isEqual(t, githubapp.ErrLeaseRevoked(lease.ID), lease.Renew())
+
+	_, ok = gh.GetLease(lease.ID)
+	isEqual(t, false, ok)
+}
+
+func TestLeaseContext(t *testing.T) {
+	type key struct{}
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsRevokeAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		ctx           = context.WithValue(context.Background(), key{}, "value")
+		expiresAt     = time.Now().Add(1 * time.Hour)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token"), ExpiresAt: &expiresAt}, nil, nil)
+
+	lease, err := gh.LeaseContext(ctx, githubapp.Scope{Owner: "owner"})
+	noError(t, err)
+	noError(t, lease.RenewContext(ctx))
+	noError(t, lease.RevokeContext(ctx))
+
+	for i := 0; i < client.CreateInstallationTokenCallCount(); i++ {
+		got, _, _ := client.CreateInstallationTokenArgsForCall(i)
+		isEqual(t, "value", got.Value(key{}))
+	}
+	isEqual(t, "value", tokenClient.RevokeInstallationTokenArgsForCall(0).Value(key{}))
+}
+
+func TestTokenLifetime(t *testing.T) {
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsRevokeAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory), githubapp.WithTokenLifetime(50*time.Millisecond))
+		expiresAt     = time.Now().Add(1 * time.Hour)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token"), ExpiresAt: &expiresAt}, nil, nil)
+
+	token, err := gh.CreateInstallationToken("owner", nil, nil)
+	noError(t, err)
+	if token.GetExpiresAt().After(time.Now().Add(50 * time.Millisecond)) {
+		t.Errorf("expected expiry to be limited to the token lifetime, got: %s", token.GetExpiresAt())
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	isEqual(t, 1, tokenClient.RevokeInstallationTokenCallCount())
+}
+
+func TestWatchToken(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client, githubapp.WithInstallationClientFactory(func(string) githubapp.AppsTokenAPI {
+			return &fakes.FakeAppsRevokeAPI{}
+		}), githubapp.WithTokenLifetime(50*time.Millisecond))
+		ctx, cancel = context.WithTimeout(context.Background(), 120*time.Millisecond)
+	)
+	defer cancel()
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		return &github.InstallationToken{Token: github.String("token")}, nil, nil
+	})
+
+	var refreshed int
+	err := gh.WatchToken(ctx, githubapp.Scope{Owner: "owner"}, func(*githubapp.Token) error {
+		refreshed++
+		return nil
+	})
+	isEqual(t, context.DeadlineExceeded, err)
+	if refreshed < 2 {
+		t.Errorf("expected the token to be refreshed, got %d tokens", refreshed)
+	}
+}
+
+func TestPrewarm(t *testing.T) {
+	var (
+		client      = &fakes.FakeAppsJWTAPI{}
+		gh          = githubapp.New(client)
+		ctx, cancel = context.WithCancel(context.Background())
+		expiresAt   = time.Now().Add(1 * time.Hour)
+	)
+	defer cancel()
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenCalls(func(context.Context, int64, *github.InstallationTokenOptions) (*github.InstallationToken, *github.Response, error) {
+		token := fmt.Sprintf("token-%d", client.CreateInstallationTokenCallCount())
+		return &github.InstallationToken{Token: github.String(token), ExpiresAt: &expiresAt}, nil, nil
+	})
+
+	lease, err := gh.Prewarm(ctx, githubapp.Scope{Owner: "owner"}, githubapp.Every(50*time.Millisecond), 10*time.Millisecond)
+	noError(t, err)
+	isEqual(t, "token-1", lease.Token().GetToken())
+
+	time.Sleep(120 * time.Millisecond)
+	cancel()
+	if n := client.CreateInstallationTokenCallCount(); n < 3 {
+		t.Errorf("expected the lease to be renewed before each run, got %d tokens", n)
+	}
+	if lease.Token().GetToken() == "token-1" {
+		t.Error("expected the lease to have a new token")
+	}
+}
+
+func TestPrewarmSchedule(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+	)
+
+	_, err := gh.Prewarm(context.TODO(), githubapp.Scope{Owner: "owner"}, func(after time.Time) time.Time { return after }, 0)
+	if err == nil {
+		t.Error("expected an error for a schedule that does not advance")
+	}
+	isEqual(t, 0, client.CreateInstallationTokenCallCount())
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Every to panic for a non-positive interval")
+		}
+	}()
+	githubapp.Every(0)
+}
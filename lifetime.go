@@ -0,0 +1,38 @@
+package githubapp
+
+import (
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// WithTokenLifetime limits the lifetime of installation tokens returned by the App to the given duration, for policies that
+// require credentials that live shorter than the one hour granted by Github. The expiry of the returned tokens is adjusted,
+// and they are revoked when the lifetime has passed.
+func WithTokenLifetime(lifetime time.Duration) option {
+	return func(a *App) {
+		a.tokenLifetime = lifetime
+	}
+}
+
+// newToken records the usage of an installation token issued for the owner, and limits its lifetime if WithTokenLifetime is used.
+func (a *App) newToken(owner string, installationToken *github.InstallationToken) *Token {
+	a.recordUsage(owner)
+
+	if a.tokenLifetime > 0 {
+		deadline := time.Now().Add(a.tokenLifetime)
+		if installationToken.ExpiresAt == nil || installationToken.ExpiresAt.After(deadline) {
+			installationToken.ExpiresAt = &deadline
+		}
+		a.revokeAt(owner, installationToken.GetToken(), *installationToken.ExpiresAt)
+	}
+	return &Token{InstallationToken: installationToken}
+}
+
+// expiryMargin returns how long before expiry a token that is reused should be replaced.
+func (a *App) expiryMargin() time.Duration {
+	if margin := a.tokenLifetime / 5; a.tokenLifetime > 0 && margin < tokenExpiryMargin {
+		return margin
+	}
+	return tokenExpiryMargin
+}
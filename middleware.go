@@ -0,0 +1,48 @@
+package githubapp
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware returns HTTP middleware that resolves the installation for the owner and attaches it to the
+// request context, together with the App and an InstallationClient that is authenticated as the installation.
+// Use InstallationFromContext and ClientFromContext to retrieve them in the handler. If the installation cannot be
+// resolved, the request fails with 404 if it is not found, 403 if the owner is not allowed, and 500 otherwise.
+func (a *App) Middleware(owner string) func(http.Handler) http.Handler {
+	client := newInstallationClient(&http.Client{
+		Transport: a.InstallationTransport(owner),
+	})
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			installation, err := a.InstallationContext(r.Context(), owner)
+			if err != nil {
+				status := errorStatus(err)
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			ctx := NewContext(r.Context(), a)
+			ctx = NewInstallationContext(ctx, installation)
+			ctx = context.WithValue(ctx, clientContextKey, client)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// errorStatus returns the HTTP status code for an error returned when resolving the installation.
+func errorStatus(err error) int {
+	switch err.(type) {
+	case ErrInstallationNotFound:
+		return http.StatusNotFound
+	case ErrOwnerNotAllowed:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ClientFromContext returns the InstallationClient stored in ctx by Middleware, if any.
+func ClientFromContext(ctx context.Context) (*InstallationClient, bool) {
+	client, ok := ctx.Value(clientContextKey).(*InstallationClient)
+	return client, ok
+}
@@ -0,0 +1,84 @@
+package githubapp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestMiddleware(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	var called bool
+	handler := gh.Middleware("owner")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+
+		installation, ok := githubapp.InstallationFromContext(r.Context())
+		isEqual(t, true, ok)
+		isEqual(t, int64(23), installation.ID)
+
+		_, ok = githubapp.ClientFromContext(r.Context())
+		isEqual(t, true, ok)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+	isEqual(t, http.StatusOK, rec.Code)
+	isEqual(t, true, called)
+
+	rec = httptest.NewRecorder()
+	gh.Middleware("other")(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+	isEqual(t, http.StatusNotFound, rec.Code)
+	isEqual(t, "Not Found\n", rec.Body.String())
+}
+
+func TestMiddlewareErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		owner       string
+		err         error
+		expected    int
+	}{
+		{
+			description: "owner not allowed",
+			owner:       "other",
+			expected:    http.StatusForbidden,
+		},
+		{
+			description: "failure to list installations",
+			owner:       "owner",
+			err:         errors.New("secret details"),
+			expected:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				client = &fakes.FakeAppsJWTAPI{}
+				gh     = githubapp.New(client, githubapp.WithOwners("owner"))
+			)
+
+			client.ListInstallationsReturns(nil, nil, tc.err)
+
+			rec := httptest.NewRecorder()
+			gh.Middleware(tc.owner)(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+			isEqual(t, tc.expected, rec.Code)
+			isEqual(t, http.StatusText(tc.expected)+"\n", rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package mirror keeps local mirrors of Github repositories up to date using Github App installation credentials. The
+// credentials are passed to git with GIT_CONFIG_COUNT, which requires git 2.31 or later.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+
+	"github.com/telia-oss/githubapp"
+)
+
+// Mirror is a bare mirror of a repository on disk, which is cloned and fetched by shelling out to git.
+type Mirror struct {
+	App        *githubapp.App
+	Owner      string
+	Repository string
+
+	// Dir is the path of the bare repository.
+	Dir string
+
+	// Git is the git binary to use, and defaults to "git" in PATH.
+	Git string
+}
+
+// Sync clones the repository to Dir if it does not exist, and otherwise fetches all refs (pruning deleted ones). The installation
+// token is passed to git through the environment, so it is neither persisted in the git config nor visible in the process list.
+func (m *Mirror) Sync(ctx context.Context) error {
+	remote, err := m.App.CloneURL(ctx, m.Owner, m.Repository)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(remote.URL)
+	if err != nil {
+		return err
+	}
+	token, _ := u.User.Password()
+
+	if _, err := os.Stat(m.Dir); os.IsNotExist(err) {
+		return m.git(ctx, token, "clone", "--mirror", remote.Display, m.Dir)
+	}
+	return m.git(ctx, token, "-C", m.Dir, "remote", "update", "--prune")
+}
+
+// git runs git with the arguments, authenticated with the token.
+func (m *Mirror) git(ctx context.Context, token string, args ...string) error {
+	bin := m.Git
+	if bin == "" {
+		bin = "git"
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+base64.StdEncoding.EncodeToString([]byte("x-access-token:"+token)),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %s: %s", args[0], err, githubapp.Redact(stderr.String()))
+	}
+	return nil
+}
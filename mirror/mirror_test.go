@@ -0,0 +1,94 @@
+package mirror_test
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+	"github.com/telia-oss/githubapp/mirror"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func isEqual(t *testing.T, expected, got interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("\nexpected:\n%v\n\ngot:\n%v", expected, got)
+	}
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the git stub is a shell script")
+	}
+
+	var (
+		client        = &fakes.FakeAppsJWTAPI{}
+		tokenClient   = &fakes.FakeAppsTokenAPI{}
+		clientFactory = func(string) githubapp.AppsTokenAPI { return tokenClient }
+		gh            = githubapp.New(client, githubapp.WithInstallationClientFactory(clientFactory))
+		expiresAt     = time.Now().Add(1 * time.Hour)
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("ghs_token"), ExpiresAt: &expiresAt}, nil, nil)
+
+	tokenClient.ListReposReturns(&github.ListRepositories{
+		Repositories: []*github.Repository{{ID: github.Int64(1), Name: github.String("repository")}},
+	}, &github.Response{}, nil)
+
+	tmp, err := ioutil.TempDir("", "mirror")
+	noError(t, err)
+	defer os.RemoveAll(tmp)
+
+	// The stub records its arguments and the environment set by Mirror, one per line.
+	var (
+		git  = filepath.Join(tmp, "git")
+		args = filepath.Join(tmp, "args")
+		env  = filepath.Join(tmp, "env")
+	)
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + args + "\nenv | grep -e '^GIT_CONFIG_' -e '^GIT_TERMINAL_PROMPT=' | sort > " + env + "\n"
+	noError(t, ioutil.WriteFile(git, []byte(script), 0700))
+
+	m := &mirror.Mirror{App: gh, Owner: "owner", Repository: "repository", Dir: filepath.Join(tmp, "repository.git"), Git: git}
+
+	noError(t, m.Sync(context.TODO()))
+	isEqual(t, []string{"clone", "--mirror", "https://github.com/owner/repository.git", m.Dir}, lines(t, args))
+	isEqual(t, []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:ghs_token")),
+		"GIT_TERMINAL_PROMPT=0",
+	}, lines(t, env))
+
+	noError(t, os.Mkdir(m.Dir, 0700))
+	noError(t, m.Sync(context.TODO()))
+	isEqual(t, []string{"-C", m.Dir, "remote", "update", "--prune"}, lines(t, args))
+}
+
+// lines returns the lines of the file.
+func lines(t *testing.T, path string) []string {
+	b, err := ioutil.ReadFile(path)
+	noError(t, err)
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
@@ -0,0 +1,40 @@
+package githubapp
+
+import (
+	"strings"
+	"time"
+)
+
+// StaticInstallation is an installation that is known in advance, for use with WithStaticInstallations.
+type StaticInstallation struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+
+	// Repositories maps the names of the repositories selected for the installation to their IDs. If it is nil, the
+	// installation is assumed to have access to all repositories for the owner, and Repositories returns an empty list.
+	Repositories map[string]int64 `json:"repositories,omitempty"`
+}
+
+// WithStaticInstallations makes the App use the given installations instead of listing them, and never list installations or
+// repositories. The only requests made with the App JWT client are to create installation tokens. It is intended for
+// environments that want to keep API usage to a minimum, or where the installations are managed elsewhere (e.g. read from a
+// configuration file).
+func WithStaticInstallations(installations ...StaticInstallation) option {
+	return func(a *App) {
+		now := time.Now()
+		a.offline = true
+		a.installs = make(map[string]*installation, len(installations))
+		a.installsUpdatedAt = now
+		for _, s := range installations {
+			i := &installation{ID: s.ID, Owner: strings.ToLower(s.Owner), RepositorySelection: "all", RepositoriesUpdatedAt: now}
+			if s.Repositories != nil {
+				i.RepositorySelection = "selected"
+				i.Repositories = make(map[string]repository, len(s.Repositories))
+				for name, id := range s.Repositories {
+					i.Repositories[name] = repository{ID: id}
+				}
+			}
+			a.installs[i.Owner] = i
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package githubapp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Permission is the name of an installation permission, as used by the Github API.
+type Permission string
+
+// Installation permissions.
+const (
+	PermissionActions                       Permission = "actions"
+	PermissionAdministration                Permission = "administration"
+	PermissionBlocking                      Permission = "blocking"
+	PermissionChecks                        Permission = "checks"
+	PermissionContents                      Permission = "contents"
+	PermissionContentReferences             Permission = "content_references"
+	PermissionDeployments                   Permission = "deployments"
+	PermissionEmails                        Permission = "emails"
+	PermissionEnvironments                  Permission = "environments"
+	PermissionFollowers                     Permission = "followers"
+	PermissionIssues                        Permission = "issues"
+	PermissionMetadata                      Permission = "metadata"
+	PermissionMembers                       Permission = "members"
+	PermissionOrganizationAdministration    Permission = "organization_administration"
+	PermissionOrganizationHooks             Permission = "organization_hooks"
+	PermissionOrganizationPlan              Permission = "organization_plan"
+	PermissionOrganizationPreReceiveHooks   Permission = "organization_pre_receive_hooks"
+	PermissionOrganizationProjects          Permission = "organization_projects"
+	PermissionOrganizationSecrets           Permission = "organization_secrets"
+	PermissionOrganizationSelfHostedRunners Permission = "organization_self_hosted_runners"
+	PermissionOrganizationUserBlocking      Permission = "organization_user_blocking"
+	PermissionPackages                      Permission = "packages"
+	PermissionPages                         Permission = "pages"
+	PermissionPullRequests                  Permission = "pull_requests"
+	PermissionRepositoryHooks               Permission = "repository_hooks"
+	PermissionRepositoryProjects            Permission = "repository_projects"
+	PermissionRepositoryPreReceiveHooks     Permission = "repository_pre_receive_hooks"
+	PermissionSecrets                       Permission = "secrets"
+	PermissionSecretScanningAlerts          Permission = "secret_scanning_alerts"
+	PermissionSecurityEvents                Permission = "security_events"
+	PermissionSingleFile                    Permission = "single_file"
+	PermissionStatuses                      Permission = "statuses"
+	PermissionTeamDiscussions               Permission = "team_discussions"
+	PermissionVulnerabilityAlerts           Permission = "vulnerability_alerts"
+	PermissionWorkflows                     Permission = "workflows"
+)
+
+// KnownPermissions returns all installation permissions that can be requested.
+func KnownPermissions() []Permission {
+	return []Permission{
+		PermissionActions,
+		PermissionAdministration,
+		PermissionBlocking,
+		PermissionChecks,
+		PermissionContents,
+		PermissionContentReferences,
+		PermissionDeployments,
+		PermissionEmails,
+		PermissionEnvironments,
+		PermissionFollowers,
+		PermissionIssues,
+		PermissionMetadata,
+		PermissionMembers,
+		PermissionOrganizationAdministration,
+		PermissionOrganizationHooks,
+		PermissionOrganizationPlan,
+		PermissionOrganizationPreReceiveHooks,
+		PermissionOrganizationProjects,
+		PermissionOrganizationSecrets,
+		PermissionOrganizationSelfHostedRunners,
+		PermissionOrganizationUserBlocking,
+		PermissionPackages,
+		PermissionPages,
+		PermissionPullRequests,
+		PermissionRepositoryHooks,
+		PermissionRepositoryProjects,
+		PermissionRepositoryPreReceiveHooks,
+		PermissionSecrets,
+		PermissionSecretScanningAlerts,
+		PermissionSecurityEvents,
+		PermissionSingleFile,
+		PermissionStatuses,
+		PermissionTeamDiscussions,
+		PermissionVulnerabilityAlerts,
+		PermissionWorkflows,
+	}
+}
+
+// PermissionLevel is the level of access that is granted for a permission.
+type PermissionLevel string
+
+// Permission levels.
+const (
+	PermissionRead  PermissionLevel = "read"
+	PermissionWrite PermissionLevel = "write"
+	PermissionAdmin PermissionLevel = "admin"
+)
+
+// Valid returns true if the permission level is known.
+func (l PermissionLevel) Valid() bool {
+	switch l {
+	case PermissionRead, PermissionWrite, PermissionAdmin:
+		return true
+	}
+	return false
+}
+
+// NewPermissions returns Permissions granting the given access levels, or an error if any of the permissions or levels are unknown.
+func NewPermissions(levels map[Permission]PermissionLevel) (*Permissions, error) {
+	p := &Permissions{}
+	for permission, level := range levels {
+		if err := p.Set(permission, level); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Set grants the access level for the permission, and returns an error if the permission or level is unknown.
+func (p *Permissions) Set(permission Permission, level PermissionLevel) error {
+	if !level.Valid() {
+		return ErrInvalidPermission{Permission: permission, Level: level}
+	}
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if permissionName(v.Type().Field(i)) == string(permission) {
+			s := string(level)
+			v.Field(i).Set(reflect.ValueOf(&s))
+			return nil
+		}
+	}
+	return ErrInvalidPermission{Permission: permission, Level: level}
+}
+
+// Validate returns an error if any of the permissions are granted with an unknown access level.
+func (p *Permissions) Validate() error {
+	for name, level := range permissionLevels(p) {
+		if !PermissionLevel(level).Valid() {
+			return ErrInvalidPermission{Permission: Permission(name), Level: PermissionLevel(level)}
+		}
+	}
+	return nil
+}
+
+// rank orders the permission levels from least to most access.
+func (l PermissionLevel) rank() int {
+	switch l {
+	case PermissionRead:
+		return 1
+	case PermissionWrite:
+		return 2
+	case PermissionAdmin:
+		return 3
+	}
+	return 0
+}
+
+// Union returns the permissions granted by either a or b, with the highest access level of the two.
+func Union(a, b *Permissions) *Permissions {
+	levels := permissionLevels(a)
+	for name, level := range permissionLevels(b) {
+		if PermissionLevel(level).rank() > PermissionLevel(levels[name]).rank() {
+			levels[name] = level
+		}
+	}
+	return fromLevels(levels)
+}
+
+// Intersect returns the permissions granted by both a and b, with the lowest access level of the two.
+func Intersect(a, b *Permissions) *Permissions {
+	levels := make(map[string]string)
+	bLevels := permissionLevels(b)
+	for name, level := range permissionLevels(a) {
+		other, ok := bLevels[name]
+		if !ok {
+			continue
+		}
+		if PermissionLevel(other).rank() < PermissionLevel(level).rank() {
+			level = other
+		}
+		levels[name] = level
+	}
+	return fromLevels(levels)
+}
+
+// Subset returns true if p does not grant any permission that is not granted by other, or with a higher access level.
+func (p *Permissions) Subset(other *Permissions) bool {
+	otherLevels := permissionLevels(other)
+	for name, level := range permissionLevels(p) {
+		if PermissionLevel(level).rank() > PermissionLevel(otherLevels[name]).rank() {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the permissions that are granted with a different access level in from and to, sorted by permission name.
+func Diff(from, to *Permissions) []PermissionDiff {
+	var diff []PermissionDiff
+	fromLevels, toLevels := permissionLevels(from), permissionLevels(to)
+	for _, name := range unionKeys(fromLevels, toLevels) {
+		if fromLevels[name] != toLevels[name] {
+			diff = append(diff, PermissionDiff{Permission: name, From: fromLevels[name], To: toLevels[name]})
+		}
+	}
+	return diff
+}
+
+// fromLevels returns the Permissions granting the access levels returned by permissionLevels.
+func fromLevels(levels map[string]string) *Permissions {
+	p := &Permissions{}
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if level, ok := levels[permissionName(v.Type().Field(i))]; ok {
+			v.Field(i).Set(reflect.ValueOf(&level))
+		}
+	}
+	return p
+}
+
+// ErrInvalidPermission is returned if a permission or permission level is unknown.
+type ErrInvalidPermission struct {
+	Permission Permission
+	Level      PermissionLevel
+}
+
+func (e ErrInvalidPermission) Error() string {
+	return fmt.Sprintf("invalid permission: '%s: %s'", e.Permission, e.Level)
+}
+
+// permissionLevels returns the access level for each permission that is set, keyed by the permission name used by the Github API.
+func permissionLevels(p *Permissions) map[string]string {
+	levels := make(map[string]string)
+	if p == nil {
+		return levels
+	}
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); !f.IsNil() {
+			levels[permissionName(v.Type().Field(i))] = f.Elem().String()
+		}
+	}
+	return levels
+}
+
+// permissionName returns the Github API name of the permission field.
+func permissionName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
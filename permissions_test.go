@@ -0,0 +1,116 @@
+package githubapp_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+	"github.com/telia-oss/githubapp/fakes"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestKnownPermissions(t *testing.T) {
+	// Every field of the go-github permissions should have a constant.
+	isEqual(t, reflect.TypeOf(githubapp.Permissions{}).NumField(), len(githubapp.KnownPermissions()))
+
+	for _, permission := range githubapp.KnownPermissions() {
+		_, err := githubapp.NewPermissions(map[githubapp.Permission]githubapp.PermissionLevel{
+			permission: githubapp.PermissionRead,
+		})
+		noError(t, err)
+	}
+}
+
+func TestNewPermissions(t *testing.T) {
+	permissions, err := githubapp.NewPermissions(map[githubapp.Permission]githubapp.PermissionLevel{
+		githubapp.PermissionContents:     githubapp.PermissionWrite,
+		githubapp.PermissionPullRequests: githubapp.PermissionRead,
+	})
+	noError(t, err)
+	isEqual(t, &githubapp.Permissions{
+		Contents:     github.String("write"),
+		PullRequests: github.String("read"),
+	}, permissions)
+
+	_, err = githubapp.NewPermissions(map[githubapp.Permission]githubapp.PermissionLevel{
+		"pull_request": githubapp.PermissionRead,
+	})
+	isEqual(t, "invalid permission: 'pull_request: read'", err.Error())
+
+	err = (&githubapp.Permissions{Contents: github.String("wirte")}).Validate()
+	isEqual(t, githubapp.ErrInvalidPermission{Permission: githubapp.PermissionContents, Level: "wirte"}, err)
+}
+
+func TestPermissionAlgebra(t *testing.T) {
+	a := &githubapp.Permissions{
+		Contents: github.String("write"),
+		Metadata: github.String("read"),
+	}
+	b := &githubapp.Permissions{
+		Contents: github.String("read"),
+		Issues:   github.String("write"),
+	}
+
+	isEqual(t, &githubapp.Permissions{
+		Contents: github.String("write"),
+		Metadata: github.String("read"),
+		Issues:   github.String("write"),
+	}, githubapp.Union(a, b))
+
+	isEqual(t, &githubapp.Permissions{
+		Contents: github.String("read"),
+	}, githubapp.Intersect(a, b))
+
+	isEqual(t, true, githubapp.Intersect(a, b).Subset(a))
+	isEqual(t, true, a.Subset(githubapp.Union(a, b)))
+	isEqual(t, false, a.Subset(b))
+	isEqual(t, true, (*githubapp.Permissions)(nil).Subset(b))
+
+	isEqual(t, []githubapp.PermissionDiff{
+		{Permission: "contents", From: "write", To: "read"},
+		{Permission: "issues", From: "", To: "write"},
+		{Permission: "metadata", From: "read", To: ""},
+	}, githubapp.Diff(a, b))
+}
+
+func TestScopedApp(t *testing.T) {
+	var (
+		client = &fakes.FakeAppsJWTAPI{}
+		gh     = githubapp.New(client)
+		scoped = gh.Scoped("owner", &githubapp.Permissions{
+			Contents: github.String("read"),
+			Metadata: github.String("read"),
+		})
+	)
+
+	client.ListInstallationsReturns([]*github.Installation{{
+		ID:      github.Int64(23),
+		Account: &github.User{Login: github.String("owner")},
+	}}, &github.Response{}, nil)
+
+	client.CreateInstallationTokenReturns(&github.InstallationToken{Token: github.String("token")}, nil, nil)
+
+	_, err := scoped.CreateInstallationToken(nil, &githubapp.Permissions{Contents: github.String("read")})
+	noError(t, err)
+
+	_, err = scoped.CreateInstallationToken(nil, nil)
+	noError(t, err)
+	_, _, opts := client.CreateInstallationTokenArgsForCall(1)
+	isEqual(t, github.String("read"), opts.Permissions.Metadata)
+
+	_, err = scoped.CreateInstallationToken(nil, &githubapp.Permissions{
+		Contents: github.String("write"),
+		Issues:   github.String("read"),
+	})
+	isEqual(t, "permissions exceed the maximum for 'owner': 'contents: write', 'issues: read'", err.Error())
+	isEqual(t, 2, client.CreateInstallationTokenCallCount())
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	_, err = scoped.InstallationClientContext(ctx, nil, nil)
+	noError(t, err)
+	ctx, _, _ = client.CreateInstallationTokenArgsForCall(2)
+	isEqual(t, "value", ctx.Value(key{}))
+}
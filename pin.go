@@ -0,0 +1,86 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// WithPinnedInstallations pins owners to installation IDs. Pinned owners are resolved without listing installations, and
+// their installation is never replaced by the one that is listed for the owner. The installations are still listed once if
+// the metadata of a pinned installation is needed, e.g. for Installation or tokens scoped to repositories. If a pinned
+// installation no longer exists, creating a token for the owner fails with *ErrPinnedInstallationNotFound.
+func WithPinnedInstallations(pins map[string]int64) option {
+	return func(a *App) {
+		a.pins = make(map[string]int64, len(pins))
+		for owner, id := range pins {
+			a.pins[strings.ToLower(owner)] = id
+		}
+	}
+}
+
+// pinnedInstallation returns the cached installation for the owner if it is pinned, and adds it to the cache if needed.
+// The caller must hold a.mu.
+func (a *App) pinnedInstallation(owner string) (*installation, bool) {
+	id, ok := a.pins[owner]
+	if !ok {
+		return nil, false
+	}
+	i, ok := a.installs[owner]
+	if !ok || i.ID != id {
+		if a.installs == nil {
+			a.installs = make(map[string]*installation)
+		}
+		i = &installation{ID: id, Owner: owner}
+		a.installs[owner] = i
+	}
+	return i, true
+}
+
+// describePinned lists the installations if the owner is pinned and they have not been listed yet, so that the cached
+// installation for the owner has the same metadata (e.g. RepositorySelection) as the listed installations. The caller must
+// hold a.mu, which is released while the installations are listed.
+func (a *App) describePinned(ctx context.Context, owner string) error {
+	if _, ok := a.pins[owner]; !ok || !a.installsUpdatedAt.IsZero() {
+		return nil
+	}
+	return a.updateInstallations(ctx)
+}
+
+// keepPinned makes the listed installations keep the cached installations for pinned owners, unless the pinned installation
+// was listed, and drops installations listed for pinned owners that have not been cached yet.
+func (a *App) keepPinned(installs map[string]*installation) {
+	for owner, id := range a.pins {
+		if i, ok := installs[owner]; ok && i.ID == id {
+			continue
+		}
+		if i, ok := a.installs[owner]; ok {
+			installs[owner] = i
+		} else {
+			delete(installs, owner)
+		}
+	}
+}
+
+// pinnedError returns ErrPinnedInstallationNotFound if the request failed because the installation that the owner is
+// pinned to was not found, or err otherwise.
+func (a *App) pinnedError(owner string, id int64, response *github.Response, err error) error {
+	if response != nil && response.StatusCode == http.StatusNotFound && a.pins[owner] == id {
+		return &ErrPinnedInstallationNotFound{Owner: owner, ID: id}
+	}
+	return err
+}
+
+// ErrPinnedInstallationNotFound is returned if the installation that an owner is pinned to with WithPinnedInstallations
+// does not exist (e.g. because the App was reinstalled and has a new installation ID).
+type ErrPinnedInstallationNotFound struct {
+	Owner string
+	ID    int64
+}
+
+func (e *ErrPinnedInstallationNotFound) Error() string {
+	return fmt.Sprintf("pinned installation %d not found for owner: '%s'", e.ID, e.Owner)
+}
@@ -0,0 +1,61 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Schedule returns the next time a scheduled job runs after the given time.
+type Schedule func(after time.Time) time.Time
+
+// Every returns a Schedule for jobs that run at a fixed interval, aligned to the interval since the zero time (e.g. on the
+// hour for an interval of one hour). It panics if the interval is not positive.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("githubapp: non-positive interval for Every")
+	}
+	return func(after time.Time) time.Time {
+		return after.Truncate(interval).Add(interval)
+	}
+}
+
+// Prewarm returns a Lease for the scope which is renewed the given lead time before each run of the schedule, until the
+// context is done or the Lease is revoked. Scheduled jobs can use the token of the Lease without waiting for a new token to be created. If a renewal
+// fails the previous token is kept, so jobs should check that the Lease has not expired. An error is returned if the schedule
+// does not return a time after the current time, and the Lease is no longer renewed if it stops doing so later on.
+func (a *App) Prewarm(ctx context.Context, scope Scope, schedule Schedule, lead time.Duration) (*Lease, error) {
+	now := time.Now()
+	first := schedule(now)
+	if !first.After(now) {
+		return nil, fmt.Errorf("schedule does not advance after: %s", now.Format(time.RFC3339))
+	}
+	lease, err := a.LeaseContext(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		next := first
+		for {
+			timer := time.NewTimer(time.Until(next.Add(-lead)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			// Other errors are ignored since the previous token remains usable until it expires.
+			if err := lease.RenewContext(ctx); err != nil {
+				if _, ok := err.(ErrLeaseRevoked); ok {
+					return
+				}
+			}
+			after := schedule(next)
+			if !after.After(next) {
+				return
+			}
+			next = after
+		}
+	}()
+	return lease, nil
+}
@@ -0,0 +1,120 @@
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// appsHost is the host that requests made with the App JWT client are accounted to by WithRateLimit. The AppsJWTAPI client
+// does not expose its base URL, so this is also used if the client is configured for Github Enterprise Server.
+const appsHost = "api.github.com"
+
+// WithRateLimit limits the rate of outgoing requests made by the App to qps requests per second per host, with bursts of up to burst
+// requests. It applies to calls made with the App JWT client, the default installation clients, and transports returned by the App.
+// Installation clients returned by a custom InstallationClientFactory can use NewRateLimitTransport to share the same limits.
+// Calls made with the App JWT client are always accounted to api.github.com, also when it is configured for Github Enterprise
+// Server. If qps is zero or negative, requests are not rate limited.
+func WithRateLimit(qps float64, burst int) option {
+	return func(a *App) {
+		a.limiter = newRateLimiter(qps, burst)
+	}
+}
+
+// NewRateLimitTransport returns a http.RoundTripper that limits requests to qps requests per second per host, with bursts of
+// up to burst requests. If base is nil, http.DefaultTransport is used. If qps is zero or negative, base is returned as is.
+func NewRateLimitTransport(base http.RoundTripper, qps float64, burst int) http.RoundTripper {
+	return newRateLimiter(qps, burst).transport(base)
+}
+
+// rateLimiter is a token bucket rate limiter with one bucket per host. A nil rateLimiter does not limit.
+type rateLimiter struct {
+	qps   float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a rateLimiter, or nil if qps is not positive.
+func newRateLimiter(qps float64, burst int) *rateLimiter {
+	if !(qps > 0) {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{qps: qps, burst: float64(burst), buckets: make(map[string]*bucket)}
+}
+
+// wait blocks until a request to the host is allowed, or the context is done.
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	if l == nil {
+		return nil
+	}
+	delay := l.reserve(host)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket for the host and returns how long the caller must wait before using it.
+func (l *rateLimiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[host] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.qps
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / l.qps * float64(time.Second))
+}
+
+// transport wraps base so that requests are rate limited. If the rateLimiter is nil, base is returned as is.
+func (l *rateLimiter) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if l == nil {
+		return base
+	}
+	return &rateLimitTransport{limiter: l, base: base}
+}
+
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,35 @@
+package githubapp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/telia-oss/githubapp"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: githubapp.NewRateLimitTransport(nil, 20, 2)}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		res, err := client.Get(server.URL)
+		noError(t, err)
+		res.Body.Close()
+	}
+
+	// The first two requests are allowed by the burst, and the remaining two are spaced out by 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransportWithoutLimit(t *testing.T) {
+	for _, qps := range []float64{0, -1} {
+		isEqual(t, http.DefaultTransport, githubapp.NewRateLimitTransport(nil, qps, 1))
+	}
+}
@@ -0,0 +1,66 @@
+package githubapp
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// rawHost is the host that the raw content of files in repositories is served from.
+const rawHost = "raw.githubusercontent.com"
+
+// RawURL returns a URL for the raw content of the file at the path and ref (branch, tag or commit) in the repository, for
+// systems that can only fetch a URL. Each call creates a new token that can only read the contents of the repository, and
+// which is revoked after the lifetime has passed. The lifetime cannot exceed the one hour granted by Github.
+func (a *App) RawURL(ctx context.Context, owner, repo, ref, file string, lifetime time.Duration) (*ExpiringURL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if lifetime <= 0 || lifetime > tokenLifetime {
+		lifetime = tokenLifetime
+	}
+
+	token, err := a.createInstallationToken(ctx, owner, []string{repo}, &Permissions{Contents: github.String(string(PermissionRead))})
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(lifetime)
+	if token.GetExpiresAt().Before(expiresAt) {
+		expiresAt = token.GetExpiresAt()
+	} else {
+		a.revokeAt(owner, token.GetToken(), expiresAt)
+	}
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   rawHost,
+		Path:   "/" + path.Join(owner, repo, ref, file),
+	}
+	display := u.String()
+	u.User = url.UserPassword("x-access-token", token.GetToken())
+
+	return &ExpiringURL{URL: u.String(), Display: display, ExpiresAt: expiresAt}, nil
+}
+
+// revokeAt revokes the installation token for the owner at the given time.
+func (a *App) revokeAt(owner, token string, at time.Time) {
+	time.AfterFunc(time.Until(at), func() {
+		// Errors are ignored since the token might have been revoked already.
+		_ = a.revokeToken(context.Background(), owner, token)
+	})
+}
+
+// revokeToken revokes the installation token for the owner, using the client returned by the InstallationClientFactory if it
+// implements AppsRevokeAPI, or the default installation client otherwise.
+func (a *App) revokeToken(ctx context.Context, owner, token string) error {
+	client, ok := a.tokenClient(owner, token).(AppsRevokeAPI)
+	if !ok {
+		client = newInstallationClient(a.installationHTTPClient(owner, token)).V3.Apps
+	}
+	response, err := client.RevokeInstallationToken(ctx)
+	a.observe(OperationRevoke, response)
+	return err
+}
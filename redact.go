@@ -0,0 +1,82 @@
+package githubapp
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// tokenPattern matches installation (ghs_) and user-to-server (ghu_) tokens.
+var tokenPattern = regexp.MustCompile(`gh[su]_[A-Za-z0-9_]+`)
+
+const redacted = "***"
+
+// Redact returns a copy of s where any installation or user-to-server tokens have been masked.
+func Redact(s string) string {
+	return string(redact([]byte(s)))
+}
+
+func redact(b []byte) []byte {
+	return tokenPattern.ReplaceAllFunc(b, func(token []byte) []byte {
+		return append(token[:4:4], redacted...)
+	})
+}
+
+// NewRedactingWriter returns a RedactingWriter that masks tokens before writing them to w.
+func NewRedactingWriter(w io.Writer) *RedactingWriter {
+	return &RedactingWriter{w: w}
+}
+
+// RedactingWriter is an io.Writer that masks tokens (see Redact) before passing output on to the
+// underlying writer. Output that might be the start of a token is held back until the next write,
+// so Flush must be called once all output has been written.
+type RedactingWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []byte
+}
+
+// Write redacts and writes p to the underlying writer.
+func (r *RedactingWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := append(r.pending, p...)
+	n := len(buf) - partialTokenLength(buf)
+
+	r.pending = append([]byte(nil), buf[n:]...)
+	if _, err := r.w.Write(redact(buf[:n])); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush redacts and writes any output that is being held back.
+func (r *RedactingWriter) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+	_, err := r.w.Write(redact(r.pending))
+	r.pending = nil
+	return err
+}
+
+// partialTokenLength returns the length of the suffix of b that could be (part of) a token
+// that continues in the next write.
+func partialTokenLength(b []byte) int {
+	if loc := tokenPattern.FindAllIndex(b, -1); len(loc) > 0 {
+		if last := loc[len(loc)-1]; last[1] == len(b) {
+			return len(b) - last[0]
+		}
+	}
+	for _, prefix := range []string{"ghs_", "ghu_", "ghs", "ghu", "gh", "g"} {
+		if bytes.HasSuffix(b, []byte(prefix)) {
+			return len(prefix)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package githubapp
+
+import (
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// requestIDHeader is the header that Github uses to identify requests, which should be included in support tickets.
+const requestIDHeader = "X-GitHub-Request-Id"
+
+// ResponseMetadata describes a response from the Github API to a request made by the App.
+type ResponseMetadata struct {
+	// Operation is the operation that the request was made for (e.g. OperationMint).
+	Operation  string
+	StatusCode int
+	RequestID  string
+
+	// RateLimit is the rate limit for the credentials used for the request, of which RateRemaining requests are left
+	// until RateReset.
+	RateLimit     int
+	RateRemaining int
+	RateReset     time.Time
+}
+
+// ResponseHook is called with the metadata of each response received from the Github API by the App, including error
+// responses. It can be used to log request IDs and rate limits. It may be called while the App is locked, and must not
+// call methods on the App.
+type ResponseHook func(ResponseMetadata)
+
+// WithResponseHook registers a ResponseHook that is called for each response from the Github API.
+func WithResponseHook(hook ResponseHook) option {
+	return func(a *App) {
+		a.responseHook = hook
+	}
+}
+
+// observe passes the metadata of the response to the ResponseHook, if any. Requests that did not get a response are ignored.
+func (a *App) observe(operation string, response *github.Response) {
+	if a.responseHook == nil || response == nil || response.Response == nil {
+		return
+	}
+	a.responseHook(ResponseMetadata{
+		Operation:     operation,
+		StatusCode:    response.StatusCode,
+		RequestID:     response.Header.Get(requestIDHeader),
+		RateLimit:     response.Rate.Limit,
+		RateRemaining: response.Rate.Remaining,
+		RateReset:     response.Rate.Reset.Time,
+	})
+}
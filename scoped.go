@@ -0,0 +1,73 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ScopedApp is a restricted handle for an App, which can only create installation tokens for a single owner within
+// a permission ceiling. It can be handed to components that should not have full access to the App.
+type ScopedApp struct {
+	app            *App
+	owner          string
+	maxPermissions *Permissions
+}
+
+// Scoped returns a ScopedApp for the owner, which only allows tokens with a subset of maxPermissions. If maxPermissions is nil,
+// tokens can be granted any of the permissions of the installation.
+func (a *App) Scoped(owner string, maxPermissions *Permissions) *ScopedApp {
+	return &ScopedApp{app: a, owner: owner, maxPermissions: maxPermissions}
+}
+
+// Owner returns the owner that the ScopedApp is restricted to.
+func (s *ScopedApp) Owner() string {
+	return s.owner
+}
+
+// CreateInstallationToken returns a new installation token for the owner, scoped to the provided repositories and permissions.
+// If permissions is nil, the token is granted the maximum permissions of the ScopedApp.
+func (s *ScopedApp) CreateInstallationToken(repositories []string, permissions *Permissions) (*Token, error) {
+	return s.CreateInstallationTokenContext(context.Background(), repositories, permissions)
+}
+
+// CreateInstallationTokenContext is like CreateInstallationToken, but uses the context for all requests made to create the token.
+func (s *ScopedApp) CreateInstallationTokenContext(ctx context.Context, repositories []string, permissions *Permissions) (*Token, error) {
+	if permissions == nil {
+		permissions = s.maxPermissions
+	}
+	if s.maxPermissions != nil && !permissions.Subset(s.maxPermissions) {
+		return nil, &ErrPermissionsExceeded{Owner: s.owner, Permissions: Diff(s.maxPermissions, Union(s.maxPermissions, permissions))}
+	}
+	return s.app.CreateInstallationTokenContext(ctx, s.owner, repositories, permissions)
+}
+
+// InstallationClient returns a client that is authenticated with a new installation token (see CreateInstallationToken).
+func (s *ScopedApp) InstallationClient(repositories []string, permissions *Permissions) (*InstallationClient, error) {
+	return s.InstallationClientContext(context.Background(), repositories, permissions)
+}
+
+// InstallationClientContext is like InstallationClient, but uses the context for all requests made to create the token.
+func (s *ScopedApp) InstallationClientContext(ctx context.Context, repositories []string, permissions *Permissions) (*InstallationClient, error) {
+	token, err := s.CreateInstallationTokenContext(ctx, repositories, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return newInstallationClient(s.app.installationHTTPClient(s.owner, token.GetToken())), nil
+}
+
+// ErrPermissionsExceeded is returned if a ScopedApp is asked for permissions beyond its maximum permissions.
+type ErrPermissionsExceeded struct {
+	Owner string
+
+	// Permissions that were requested with a higher access level (To) than allowed (From).
+	Permissions []PermissionDiff
+}
+
+func (e *ErrPermissionsExceeded) Error() string {
+	var permissions []string
+	for _, p := range e.Permissions {
+		permissions = append(permissions, fmt.Sprintf("%s: %s", p.Permission, p.To))
+	}
+	return fmt.Sprintf("permissions exceed the maximum for '%s': '%s'", e.Owner, strings.Join(permissions, "', '"))
+}
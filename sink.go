@@ -0,0 +1,28 @@
+package githubapp
+
+import (
+	"github.com/telia-oss/githubapp/internal/atomicfile"
+)
+
+// TokenSink receives installation tokens that are pushed to consumers which cannot request them directly.
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fakes/fake_token_sink.go . TokenSink
+type TokenSink interface {
+	Write(scope Scope, token *Token) error
+}
+
+// FileSink is a TokenSink that writes the token to a file which is only readable by the current user.
+type FileSink struct {
+	// Path returns the path of the file to write the token for the scope to.
+	Path func(scope Scope) string
+}
+
+// NewFileSink returns a FileSink that writes tokens to the given path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: func(Scope) string { return path }}
+}
+
+// Write atomically replaces the file for the scope with the token.
+func (s *FileSink) Write(scope Scope, token *Token) error {
+	return atomicfile.Write(s.Path(scope), token.GetToken())
+}
@@ -0,0 +1,36 @@
+package githubapp_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/telia-oss/githubapp"
+
+	"github.com/google/go-github/v41/github"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "githubapp")
+	noError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	sink := githubapp.NewFileSink(path)
+
+	for _, token := range []string{"first", "second"} {
+		err := sink.Write(githubapp.Scope{Owner: "owner"}, &githubapp.Token{
+			InstallationToken: &github.InstallationToken{Token: github.String(token)},
+		})
+		noError(t, err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	noError(t, err)
+	isEqual(t, "second", string(b))
+
+	info, err := os.Stat(path)
+	noError(t, err)
+	isEqual(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,57 @@
+package githubapp
+
+import (
+	"context"
+	"time"
+)
+
+// Stats summarises the installations of the App and the state of the cache.
+type Stats struct {
+	// Installations is the total number of installations, of which AllRepositories have access to all repositories
+	// for the owner, SelectedRepositories have access to selected repositories, and Suspended are suspended.
+	Installations        int
+	AllRepositories      int
+	SelectedRepositories int
+	Suspended            int
+
+	// Repositories is the total number of repositories that are cached, for the installations in RepositoriesUpdatedAt.
+	Repositories int
+
+	// InstallationsUpdatedAt is when the installations were last refreshed, and RepositoriesUpdatedAt when the
+	// repositories for each owner were last refreshed (owners that have not been cached are omitted).
+	InstallationsUpdatedAt time.Time
+	RepositoriesUpdatedAt  map[string]time.Time
+}
+
+// Stats returns a summary of the installations of the App, refreshing the installations if needed. Repositories are
+// not fetched, so the repository counts only include installations that have been cached.
+func (a *App) Stats(ctx context.Context) (*Stats, error) {
+	a.mu.Lock()
+	defer a.unlock()
+
+	if err := a.updateInstallations(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		Installations:          len(a.installs),
+		InstallationsUpdatedAt: a.installsUpdatedAt,
+		RepositoriesUpdatedAt:  make(map[string]time.Time),
+	}
+	for _, i := range a.installs {
+		switch i.RepositorySelection {
+		case "all":
+			stats.AllRepositories++
+		case "selected":
+			stats.SelectedRepositories++
+		}
+		if i.Suspended {
+			stats.Suspended++
+		}
+		if i.Repositories != nil {
+			stats.Repositories += len(i.Repositories)
+			stats.RepositoriesUpdatedAt[i.Owner] = i.RepositoriesUpdatedAt
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+package githubapp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithTokenCache makes CreateInstallationToken reuse the token for the same owner, repositories and permissions until it is
+// within skew of its expiry, instead of creating a new token on each call. Tokens returned by the cache are shared between
+// callers, and should not be revoked. Leases, clone URLs and the other helpers of the App always use tokens of their own.
+func WithTokenCache(skew time.Duration) option {
+	return func(a *App) {
+		a.tokenCache = &tokenCache{skew: skew, tokens: make(map[string]*Token)}
+	}
+}
+
+// tokenCache holds installation tokens by scope. A nil tokenCache does not cache.
+type tokenCache struct {
+	skew time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]*Token
+}
+
+// get returns a copy of the cached token for the key, if it is not about to expire.
+func (c *tokenCache) get(key string) (*Token, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[key]
+	if !ok || time.Until(token.GetExpiresAt()) <= c.skew {
+		return nil, false
+	}
+	return &Token{InstallationToken: token.InstallationToken, SkippedRepositories: token.SkippedRepositories}, true
+}
+
+// put caches the token for the key, and removes tokens that have expired.
+func (c *tokenCache) put(key string, token *Token) {
+	if c == nil || token.ExpiresAt == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, t := range c.tokens {
+		if !t.GetExpiresAt().After(time.Now()) {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = token
+}
+
+// tokenCacheKey returns the cache key for a token scoped to the owner, repositories and permissions.
+func tokenCacheKey(owner string, repositories []string, permissions *Permissions) string {
+	repos := append([]string(nil), repositories...)
+	sort.Strings(repos)
+
+	var levels []string
+	for name, level := range permissionLevels(permissions) {
+		levels = append(levels, name+"="+level)
+	}
+	sort.Strings(levels)
+
+	return strings.ToLower(owner) + "|" + strings.Join(repos, ",") + "|" + strings.Join(levels, ",")
+}
@@ -0,0 +1,158 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+const (
+	// tokenLifetime is how long installation tokens issued by Github are valid.
+	tokenLifetime = 1 * time.Hour
+
+	// tokenExpiryMargin is how long before expiry a token is considered stale and replaced.
+	tokenExpiryMargin = 5 * time.Minute
+)
+
+// OwnerFunc returns the owner whose installation should be used to authenticate the request.
+type OwnerFunc func(req *http.Request) (string, error)
+
+// OwnerFromHeader returns an OwnerFunc that reads the owner from the given request header. The header
+// is removed before the request is sent to Github.
+func OwnerFromHeader(key string) OwnerFunc {
+	return func(req *http.Request) (string, error) {
+		owner := req.Header.Get(key)
+		if owner == "" {
+			return "", fmt.Errorf("missing owner header: '%s'", key)
+		}
+		req.Header.Del(key)
+		return owner, nil
+	}
+}
+
+// Transport returns a http.RoundTripper that authenticates each request with an installation token for
+// the owner returned by the OwnerFunc. Tokens are reused across requests for the same owner until they are
+// about to expire. If base is nil, http.DefaultTransport is used.
+func (a *App) Transport(base http.RoundTripper, owner OwnerFunc) http.RoundTripper {
+	return &transport{
+		app:    a,
+		base:   a.transport(base),
+		owner:  owner,
+		tokens: make(map[string]*ownerToken),
+	}
+}
+
+type transport struct {
+	app          *App
+	base         http.RoundTripper
+	owner        OwnerFunc
+	repositories []string
+	permissions  *Permissions
+
+	mu     sync.Mutex
+	tokens map[string]*ownerToken
+}
+
+// ownerToken holds the token for an owner. The lock is a channel with room for one value, so that waiting for it can be
+// cancelled, and so that only one token is created at a time for each owner without blocking requests for other owners.
+type ownerToken struct {
+	lock  chan struct{}
+	token *Token
+}
+
+// RoundTrip implements http.RoundTripper. Requests that were redirected to another host are sent without the installation
+// token, unless the host is overridden with WithEndpoint.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	owner, err := t.owner(r)
+	if err != nil {
+		return nil, err
+	}
+	if !t.authenticate(r) {
+		return t.base.RoundTrip(r)
+	}
+	token, err := t.token(r.Context(), owner)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Authorization", "token "+token.GetToken())
+	return t.app.concurrency.transport(owner, t.base).RoundTrip(r)
+}
+
+// authenticate returns true if the request is sent to the host of the original request, i.e. it was not redirected to another
+// host, or to a host that is overridden with WithEndpoint.
+func (t *transport) authenticate(req *http.Request) bool {
+	original := req
+	for original.Response != nil && original.Response.Request != nil {
+		original = original.Response.Request
+	}
+	if strings.EqualFold(original.URL.Host, req.URL.Host) {
+		return true
+	}
+	_, ok := t.app.endpoints[strings.ToLower(req.URL.Host)]
+	return ok
+}
+
+// token returns a valid installation token for the owner. Concurrent requests for the same owner wait for the token that is
+// being created, instead of creating one each.
+func (t *transport) token(ctx context.Context, owner string) (*Token, error) {
+	t.mu.Lock()
+	o, ok := t.tokens[owner]
+	if !ok {
+		o = &ownerToken{lock: make(chan struct{}, 1)}
+		t.tokens[owner] = o
+	}
+	t.mu.Unlock()
+
+	select {
+	case o.lock <- struct{}{}:
+		defer func() { <-o.lock }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if o.token != nil && time.Until(o.token.GetExpiresAt()) > t.app.expiryMargin() {
+		return o.token, nil
+	}
+	token, err := t.app.createInstallationToken(ctx, owner, t.repositories, t.permissions)
+	if err != nil {
+		return nil, err
+	}
+	o.token = token
+	return token, nil
+}
+
+// InstallationTransport returns a http.RoundTripper that authenticates each request with an installation token for the owner,
+// which is renewed shortly before it expires. It can be used with any http.Client, e.g. for git over HTTP or raw REST calls.
+func (a *App) InstallationTransport(owner string) http.RoundTripper {
+	return a.Transport(nil, func(*http.Request) (string, error) { return owner, nil })
+}
+
+// NewInstallationClient returns a go-github client that is authenticated as the installation for the owner, with tokens scoped to
+// the provided repositories and permissions. Unlike InstallationClient, the token is renewed shortly before it expires, so the
+// client can be kept for as long as needed. An error is returned if the first token cannot be created.
+func (a *App) NewInstallationClient(owner string, repositories []string, permissions *Permissions) (*github.Client, error) {
+	return a.NewInstallationClientContext(context.Background(), owner, repositories, permissions)
+}
+
+// NewInstallationClientContext is like NewInstallationClient, but uses the context for all requests made to create the first
+// token. Tokens that are created later use the context of the request that needs them.
+func (a *App) NewInstallationClientContext(ctx context.Context, owner string, repositories []string, permissions *Permissions) (*github.Client, error) {
+	t := &transport{
+		app:          a,
+		base:         a.transport(nil),
+		owner:        func(*http.Request) (string, error) { return owner, nil },
+		repositories: repositories,
+		permissions:  permissions,
+		tokens:       make(map[string]*ownerToken),
+	}
+	if _, err := t.token(ctx, owner); err != nil {
+		return nil, err
+	}
+	return github.NewClient(&http.Client{Transport: t}), nil
+}
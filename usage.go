@@ -0,0 +1,59 @@
+package githubapp
+
+import (
+	"sort"
+	"time"
+)
+
+// Usage summarises the installation tokens that have been issued for an owner.
+type Usage struct {
+	Owner         string
+	Tokens        int
+	FirstIssuedAt time.Time
+	LastIssuedAt  time.Time
+}
+
+// UsageSink is called each time an installation token is issued to a caller, and can be used to
+// persist usage or export it as metrics.
+type UsageSink func(owner string, issuedAt time.Time)
+
+// WithUsageSink registers a UsageSink that is called in addition to the in-memory accounting.
+func WithUsageSink(sink UsageSink) option {
+	return func(a *App) {
+		a.usageSink = sink
+	}
+}
+
+// Usage returns the number of installation tokens issued per owner since the App was created, sorted by owner.
+func (a *App) Usage() []Usage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	usage := make([]Usage, 0, len(a.usage))
+	for _, u := range a.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Owner < usage[j].Owner })
+	return usage
+}
+
+// recordUsage records that a token was issued for the owner.
+func (a *App) recordUsage(owner string) {
+	now := time.Now()
+	a.mu.Lock()
+	if a.usage == nil {
+		a.usage = make(map[string]*Usage)
+	}
+	u, ok := a.usage[owner]
+	if !ok {
+		u = &Usage{Owner: owner, FirstIssuedAt: now}
+		a.usage[owner] = u
+	}
+	u.Tokens++
+	u.LastIssuedAt = now
+	a.mu.Unlock()
+
+	if a.usageSink != nil {
+		a.usageSink(owner, now)
+	}
+}
@@ -0,0 +1,33 @@
+package githubapp
+
+import (
+	"context"
+	"time"
+)
+
+// WatchToken creates an installation token for the scope and passes it to the refresh hook, and then keeps passing a new
+// token to the hook shortly before the previous one expires, until the context is done. It is intended for long downloads
+// or git operations that outlive a single token, and blocks until ctx is done or an error is returned by the hook or App.
+func (a *App) WatchToken(ctx context.Context, scope Scope, refresh func(*Token) error) error {
+	for {
+		token, err := a.createInstallationToken(ctx, scope.Owner, scope.Repositories, scope.Permissions)
+		if err != nil {
+			return err
+		}
+		if err := refresh(token); err != nil {
+			return err
+		}
+
+		expiresAt := token.GetExpiresAt()
+		if token.ExpiresAt == nil {
+			expiresAt = time.Now().Add(tokenLifetime)
+		}
+		timer := time.NewTimer(time.Until(expiresAt) - a.expiryMargin())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
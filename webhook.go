@@ -0,0 +1,182 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// installationEvent is satisfied by the go-github webhook events that are delivered for an App installation.
+type installationEvent interface {
+	GetInstallation() *github.Installation
+}
+
+// InstallationID returns the ID of the installation that a decoded webhook event (e.g. from github.ParseWebHook) was delivered for.
+func InstallationID(event interface{}) (int64, error) {
+	e, ok := event.(installationEvent)
+	if !ok || e.GetInstallation().GetID() == 0 {
+		return 0, fmt.Errorf("event does not contain an installation: %T", event)
+	}
+	return e.GetInstallation().GetID(), nil
+}
+
+// CreateInstallationTokenForEvent returns a new installation token for the installation that the webhook event was delivered for.
+// If the App is restricted with WithOwners, ErrOwnerNotAllowed is returned for installations of other owners.
+func (a *App) CreateInstallationTokenForEvent(event interface{}, permissions *Permissions) (*Token, error) {
+	return a.CreateInstallationTokenForEventContext(context.Background(), event, permissions)
+}
+
+// CreateInstallationTokenForEventContext is like CreateInstallationTokenForEvent, but uses the context for all requests made
+// to create the token.
+func (a *App) CreateInstallationTokenForEventContext(ctx context.Context, event interface{}, permissions *Permissions) (*Token, error) {
+	_, token, err := a.createInstallationTokenForEvent(ctx, event, permissions)
+	return token, err
+}
+
+// createInstallationTokenForEvent returns the owner of the installation that the event was delivered for, and a new installation token.
+func (a *App) createInstallationTokenForEvent(ctx context.Context, event interface{}, permissions *Permissions) (string, *Token, error) {
+	if err := permissions.Validate(); err != nil {
+		return "", nil, err
+	}
+	id, err := InstallationID(event)
+	if err != nil {
+		return "", nil, err
+	}
+	a.mu.Lock()
+	owner, err := a.eventOwner(ctx, event, id)
+	a.unlock()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := a.limiter.wait(ctx, appsHost); err != nil {
+		return "", nil, err
+	}
+	release, err := a.concurrency.acquire(ctx, owner)
+	if err != nil {
+		return "", nil, err
+	}
+	start := time.Now()
+	installationToken, response, err := a.client.CreateInstallationToken(ctx, id, &github.InstallationTokenOptions{
+		Permissions: (*github.InstallationPermissions)(permissions),
+	})
+	a.latencies.since(OperationMint, start)
+	a.observe(OperationMint, response)
+	release()
+	if err != nil {
+		return "", nil, err
+	}
+	return owner, a.newToken(owner, installationToken), nil
+}
+
+// eventOwner returns the owner of the installation that the event was delivered for. If the App is restricted with WithOwners,
+// the installation must belong to one of the allowed owners. The caller must hold a.mu.
+func (a *App) eventOwner(ctx context.Context, event interface{}, id int64) (string, error) {
+	if a.owners == nil {
+		if owner := a.getOwner(id); owner != "" {
+			return owner, nil
+		}
+		return strings.ToLower(event.(installationEvent).GetInstallation().GetAccount().GetLogin()), nil
+	}
+	if err := a.updateInstallations(ctx); err != nil {
+		return "", err
+	}
+	owner := a.getOwner(id)
+	if owner == "" {
+		// Only the installations of allowed owners are cached, so an unknown installation belongs to another owner.
+		return "", ErrOwnerNotAllowed(event.(installationEvent).GetInstallation().GetAccount().GetLogin())
+	}
+	return owner, nil
+}
+
+// InstallationClientForEvent returns a client that is authenticated as the installation that the webhook event was delivered for.
+func (a *App) InstallationClientForEvent(event interface{}) (*InstallationClient, error) {
+	return a.InstallationClientForEventContext(context.Background(), event)
+}
+
+// InstallationClientForEventContext is like InstallationClientForEvent, but uses the context for all requests made to create the token.
+func (a *App) InstallationClientForEventContext(ctx context.Context, event interface{}) (*InstallationClient, error) {
+	owner, token, err := a.createInstallationTokenForEvent(ctx, event, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newInstallationClient(a.installationHTTPClient(owner, token.GetToken())), nil
+}
+
+// UpdateFromEvent applies installation and installation_repositories webhook events to the cached installations and
+// repositories, so that changes can be used without waiting for the next refresh. The cache is still fully refreshed on
+// the update interval (see WithRepositoryUpdateInterval) to reconcile any missed events. Other events are ignored.
+func (a *App) UpdateFromEvent(event interface{}) {
+	a.mu.Lock()
+	defer a.unlock()
+
+	switch e := event.(type) {
+	case *github.InstallationEvent:
+		a.applyInstallationEvent(e)
+	case *github.InstallationRepositoriesEvent:
+		a.applyInstallationRepositoriesEvent(e)
+	}
+}
+
+// applyInstallationEvent updates the cached installations. The caller must hold a.mu.
+func (a *App) applyInstallationEvent(e *github.InstallationEvent) {
+	owner := strings.ToLower(e.GetInstallation().GetAccount().GetLogin())
+	if a.owners != nil && !a.owners[owner] {
+		return
+	}
+	i, ok := a.installs[owner]
+	if !ok || i.ID != e.GetInstallation().GetID() {
+		i = &installation{ID: e.GetInstallation().GetID(), Owner: owner}
+	}
+
+	switch e.GetAction() {
+	case "created":
+		i.RepositorySelection = e.GetInstallation().GetRepositorySelection()
+		i.Permissions = (*Permissions)(e.GetInstallation().Permissions)
+		i.Events = e.GetInstallation().Events
+		if a.installs != nil {
+			a.installs[owner] = i
+		}
+		a.notify(ChangeInstalled, i)
+	case "deleted":
+		delete(a.installs, owner)
+		a.notify(ChangeUninstalled, i)
+	case "suspend":
+		i.Suspended = true
+		a.notify(ChangeSuspended, i)
+	case "unsuspend":
+		i.Suspended = false
+		a.notify(ChangeUnsuspended, i)
+	}
+}
+
+// applyInstallationRepositoriesEvent updates the cached repositories. The caller must hold a.mu.
+func (a *App) applyInstallationRepositoriesEvent(e *github.InstallationRepositoriesEvent) {
+	i, ok := a.installs[a.getOwner(e.GetInstallation().GetID())]
+	if !ok {
+		return
+	}
+	if e.RepositorySelection != nil {
+		i.RepositorySelection = e.GetRepositorySelection()
+	}
+
+	var added, removed []string
+	for _, r := range e.RepositoriesAdded {
+		if i.Repositories != nil {
+			i.Repositories[r.GetName()] = repository{ID: r.GetID(), generation: i.generation}
+		}
+		added = append(added, r.GetName())
+	}
+	for _, r := range e.RepositoriesRemoved {
+		delete(i.Repositories, r.GetName())
+		removed = append(removed, r.GetName())
+	}
+	if len(added) > 0 {
+		a.notify(ChangeRepositoriesAdded, i, added...)
+	}
+	if len(removed) > 0 {
+		a.notify(ChangeRepositoriesRemoved, i, removed...)
+	}
+}
@@ -0,0 +1,121 @@
+// Package webhook parses webhook deliveries from Github defensively, so that a public webhook endpoint cannot be exhausted
+// by oversized or deeply nested payloads.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/google/go-github/v41/github"
+)
+
+// Limits for the payloads accepted by Parse. Fields that are zero (or negative) use the value from DefaultLimits, so a
+// partially filled Limits only overrides the limits that are set.
+type Limits struct {
+	// MaxBodySize is the maximum size of the payload in bytes. Github caps payloads at 25MB.
+	MaxBodySize int64
+
+	// MaxDepth is the maximum nesting of objects and arrays in the payload.
+	MaxDepth int
+}
+
+// DefaultLimits accept any payload delivered by Github.
+var DefaultLimits = Limits{
+	MaxBodySize: 25 << 20,
+	MaxDepth:    32,
+}
+
+// withDefaults returns the limits with unset fields replaced by the DefaultLimits.
+func (l Limits) withDefaults() Limits {
+	if l.MaxBodySize <= 0 {
+		l.MaxBodySize = DefaultLimits.MaxBodySize
+	}
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultLimits.MaxDepth
+	}
+	return l
+}
+
+// Parse validates the webhook delivery against the secret and limits, and returns the decoded event (see github.ParseWebHook).
+// The request body is never read beyond the size limit, and the signature is checked before the payload is decoded.
+func Parse(r *http.Request, secret []byte, limits Limits) (interface{}, error) {
+	limits = limits.withDefaults()
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil, ErrUnsupportedContentType(r.Header.Get("Content-Type"))
+	}
+	if r.ContentLength > limits.MaxBodySize {
+		return nil, ErrPayloadTooLarge(limits.MaxBodySize)
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, limits.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > limits.MaxBodySize {
+		return nil, ErrPayloadTooLarge(limits.MaxBodySize)
+	}
+
+	signature := r.Header.Get(github.SHA256SignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(github.SHA1SignatureHeader)
+	}
+	if err := github.ValidateSignature(signature, payload, secret); err != nil {
+		return nil, err
+	}
+	if err := checkDepth(payload, limits.MaxDepth); err != nil {
+		return nil, err
+	}
+	return github.ParseWebHook(github.WebHookType(r), payload)
+}
+
+// checkDepth returns an error if objects and arrays in the JSON payload are nested deeper than max.
+func checkDepth(payload []byte, max int) error {
+	var (
+		decoder = json.NewDecoder(bytes.NewReader(payload))
+		depth   int
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > max {
+				return ErrNestingTooDeep(max)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// ErrUnsupportedContentType is returned if the delivery is not JSON. Webhooks must be configured with the application/json content type.
+type ErrUnsupportedContentType string
+
+func (e ErrUnsupportedContentType) Error() string {
+	return fmt.Sprintf("unsupported content type: '%s'", string(e))
+}
+
+// ErrPayloadTooLarge is returned if the payload exceeds Limits.MaxBodySize.
+type ErrPayloadTooLarge int64
+
+func (e ErrPayloadTooLarge) Error() string {
+	return fmt.Sprintf("payload exceeds %d bytes", int64(e))
+}
+
+// ErrNestingTooDeep is returned if the payload exceeds Limits.MaxDepth.
+type ErrNestingTooDeep int
+
+func (e ErrNestingTooDeep) Error() string {
+	return fmt.Sprintf("payload is nested deeper than %d levels", int(e))
+}
@@ -0,0 +1,99 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telia-oss/githubapp/webhook"
+
+	"github.com/google/go-github/v41/github"
+)
+
+var secret = []byte("secret")
+
+func newRequest(contentType, payload string) *http.Request {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set(github.EventTypeHeader, "installation")
+	r.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestParse(t *testing.T) {
+	limits := webhook.Limits{MaxBodySize: 100, MaxDepth: 3}
+
+	tests := []struct {
+		description string
+		request     *http.Request
+		expected    error
+	}{
+		{
+			description: "parses valid payloads",
+			request:     newRequest("application/json", `{"action":"created","installation":{"id":1}}`),
+		},
+		{
+			description: "rejects other content types",
+			request:     newRequest("application/x-www-form-urlencoded", `payload={}`),
+			expected:    webhook.ErrUnsupportedContentType("application/x-www-form-urlencoded"),
+		},
+		{
+			description: "rejects large payloads",
+			request:     newRequest("application/json", `{"action":"`+strings.Repeat("a", 100)+`"}`),
+			expected:    webhook.ErrPayloadTooLarge(100),
+		},
+		{
+			description: "rejects deeply nested payloads",
+			request:     newRequest("application/json", `{"a":{"b":{"c":{}}}}`),
+			expected:    webhook.ErrNestingTooDeep(3),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			event, err := webhook.Parse(tc.request, secret, limits)
+			if !reflect.DeepEqual(err, tc.expected) {
+				t.Fatalf("expected error %v, got %v", tc.expected, err)
+			}
+			if tc.expected == nil {
+				if _, ok := event.(*github.InstallationEvent); !ok {
+					t.Errorf("expected an installation event, got %T", event)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePartialLimits(t *testing.T) {
+	for _, limits := range []webhook.Limits{{}, {MaxDepth: 10}, {MaxBodySize: 1 << 20}} {
+		event, err := webhook.Parse(newRequest("application/json", `{"action":"created","installation":{"id":1}}`), secret, limits)
+		if err != nil {
+			t.Fatalf("expected unset limits to use the defaults, got error for %+v: %s", limits, err)
+		}
+		if _, ok := event.(*github.InstallationEvent); !ok {
+			t.Errorf("expected an installation event, got %T", event)
+		}
+	}
+
+	_, err := webhook.Parse(newRequest("application/json", `{"a":{"b":{"c":{}}}}`), secret, webhook.Limits{MaxDepth: 2})
+	if !reflect.DeepEqual(err, webhook.ErrNestingTooDeep(2)) {
+		t.Errorf("expected error %v, got %v", webhook.ErrNestingTooDeep(2), err)
+	}
+}
+
+func TestParseInvalidSignature(t *testing.T) {
+	r := newRequest("application/json", `{}`)
+	r.Header.Set(github.SHA256SignatureHeader, "sha256=00")
+
+	if _, err := webhook.Parse(r, secret, webhook.DefaultLimits); err == nil {
+		t.Error("expected an error for an invalid signature")
+	}
+}